@@ -0,0 +1,356 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image-processing/v1/internal/channel"
+	"image-processing/v1/internal/histogram"
+	"image-processing/v1/internal/hsl"
+	"image-processing/v1/internal/quantize"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runCommand runs a CLI subcommand; without arguments the program runs the demo on input.jpg
+func runCommand(args []string) error {
+	switch args[0] {
+	case "histogram":
+		return histogramCommand(args[1:])
+	case "stats":
+		return statsCommand(args[1:])
+	case "hsl":
+		return hslCommand(args[1:])
+	case "colorize":
+		return colorizeCommand(args[1:])
+	case "split":
+		return splitCommand(args[1:])
+	case "merge":
+		return mergeCommand(args[1:])
+	case "swap":
+		return swapCommand(args[1:])
+	case "alpha":
+		return alphaCommand(args[1:])
+	case "quantize":
+		return quantizeCommand(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// histogramCommand exports histogram bins as CSV or JSON
+func histogramCommand(args []string) error {
+	fs := flag.NewFlagSet("histogram", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	channels := fs.String("channels", "luminance,r,g,b", "comma-separated channels: luminance, r, g, b, h, s, l, a or all")
+	bins := fs.Int("bins", 256, "number of bins")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	hists, err := histogram.ComputeAll(img, parseChannels(*channels), *bins)
+	if err != nil {
+		return err
+	}
+
+	return withOutput(*out, func(w io.Writer) error {
+		switch *format {
+		case "csv":
+			return histogram.WriteCSV(w, hists)
+		case "json":
+			return histogram.WriteJSON(w, hists)
+		default:
+			return fmt.Errorf("unknown format %q", *format)
+		}
+	})
+}
+
+// statsCommand prints per-channel statistics, unique and dominant colors as text or JSON
+func statsCommand(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	channels := fs.String("channels", "luminance,r,g,b", "comma-separated channels: luminance, r, g, b, h, s, l, a or all")
+	dominant := fs.Int("dominant", 5, "number of dominant colors to report")
+	format := fs.String("format", "text", "output format: text or json")
+	out := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	stats, err := histogram.ComputeStats(img, parseChannels(*channels), *dominant)
+	if err != nil {
+		return err
+	}
+
+	return withOutput(*out, func(w io.Writer) error {
+		switch *format {
+		case "text":
+			return histogram.WriteStatsText(w, stats)
+		case "json":
+			return histogram.WriteStatsJSON(w, stats)
+		default:
+			return fmt.Errorf("unknown format %q", *format)
+		}
+	})
+}
+
+// hslCommand rotates hue, scales saturation and shifts lightness, optionally only within a hue range
+func hslCommand(args []string) error {
+	fs := flag.NewFlagSet("hsl", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	out := fs.String("out", "output.jpg", "output image (.png or .jpg)")
+	hue := fs.Float64("hue", 0, "hue rotation in degrees")
+	saturation := fs.Float64("saturation", 0, "relative saturation change (-1 = gray, 0 = unchanged, 0.5 = +50%)")
+	lightness := fs.Float64("lightness", 0, "lightness shift (-1..1)")
+	rangeCenter := fs.Float64("range-center", 0, "center of the hue range to adjust, in degrees")
+	rangeWidth := fs.Float64("range-width", 0, "width of the hue range to adjust; 0 adjusts all hues")
+	falloff := fs.Float64("falloff", 30, "soft falloff outside the hue range, in degrees")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	adj := hsl.Adjustment{Hue: *hue, Saturation: *saturation, Lightness: *lightness}
+	var result image.Image
+	if *rangeWidth > 0 {
+		result = hsl.AdjustRange(img, hsl.HueRange{Center: *rangeCenter, Width: *rangeWidth, Falloff: *falloff}, adj)
+	} else {
+		result = hsl.Adjust(img, adj)
+	}
+	return saveOutput(result, *out)
+}
+
+// colorizeCommand tints the image with a single hue, keeping its lightness
+func colorizeCommand(args []string) error {
+	fs := flag.NewFlagSet("colorize", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	out := fs.String("out", "output.jpg", "output image (.png or .jpg)")
+	hue := fs.Float64("hue", 30, "hue in degrees")
+	saturation := fs.Float64("saturation", 0.5, "saturation (0..1)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	return saveOutput(hsl.Colorize(img, *hue, *saturation), *out)
+}
+
+// splitCommand saves each channel of the image as a separate grayscale PNG: <prefix>_<channel>.png
+func splitCommand(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	prefix := fs.String("prefix", "channel", "output file prefix")
+	space := fs.String("space", "rgba", "channels to split: rgba, rgb or hsl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	var names []string
+	var grays []*image.Gray
+	switch *space {
+	case "rgba", "rgb":
+		split := channel.Split(img)
+		names, grays = []string{"r", "g", "b", "a"}, split[:]
+		if *space == "rgb" {
+			names, grays = names[:3], grays[:3]
+		}
+	case "hsl":
+		split := channel.SplitHSL(img)
+		names, grays = []string{"h", "s", "l"}, split[:]
+	default:
+		return fmt.Errorf("unknown channel space %q", *space)
+	}
+	for i, gray := range grays {
+		if err := saveOutput(gray, fmt.Sprintf("%s_%s.png", *prefix, names[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCommand builds a color image from grayscale channel images
+func mergeCommand(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	space := fs.String("space", "rgb", "channel space: rgb (-c1 R, -c2 G, -c3 B) or hsl (-c1 H, -c2 S, -c3 L)")
+	files := [3]*string{
+		fs.String("c1", "", "first channel image (R or H)"),
+		fs.String("c2", "", "second channel image (G or S)"),
+		fs.String("c3", "", "third channel image (B or L)"),
+	}
+	alpha := fs.String("a", "", "optional alpha channel image (rgb only)")
+	out := fs.String("out", "output.png", "output image (.png or .jpg)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var planes [3]image.Image
+	for i, file := range files {
+		if *file == "" {
+			return fmt.Errorf("missing -c%d", i+1)
+		}
+		img, err := LoadImage(*file)
+		if err != nil {
+			return err
+		}
+		planes[i] = img
+	}
+
+	var merged *image.RGBA
+	var err error
+	switch *space {
+	case "rgb":
+		var a image.Image
+		if *alpha != "" {
+			if a, err = LoadImage(*alpha); err != nil {
+				return err
+			}
+		}
+		merged, err = channel.Merge(planes[0], planes[1], planes[2], a)
+	case "hsl":
+		merged, err = channel.MergeHSL(planes[0], planes[1], planes[2])
+	default:
+		return fmt.Errorf("unknown channel space %q", *space)
+	}
+	if err != nil {
+		return err
+	}
+	return saveOutput(merged, *out)
+}
+
+// swapCommand reorders the channels of the image, e.g. -order bgr
+func swapCommand(args []string) error {
+	fs := flag.NewFlagSet("swap", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	out := fs.String("out", "output.png", "output image (.png or .jpg)")
+	order := fs.String("order", "bgr", "source channel for each output channel, 3 or 4 letters from rgba")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	swapped, err := channel.Swap(img, *order)
+	if err != nil {
+		return err
+	}
+	return saveOutput(swapped, *out)
+}
+
+// alphaCommand saves the alpha channel of the image as a grayscale mask
+func alphaCommand(args []string) error {
+	fs := flag.NewFlagSet("alpha", flag.ContinueOnError)
+	in := fs.String("in", "input.png", "input image")
+	out := fs.String("out", "alpha.png", "output mask (.png or .jpg)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	mask := channel.ExtractAlpha(img)
+	// Save as grayscale so the mask is also visible when written as JPEG
+	return saveOutput(&image.Gray{Pix: mask.Pix, Stride: mask.Stride, Rect: mask.Rect}, *out)
+}
+
+// quantizeCommand reduces the image to a palette of N colors, or maps it to a fixed palette
+func quantizeCommand(args []string) error {
+	fs := flag.NewFlagSet("quantize", flag.ContinueOnError)
+	in := fs.String("in", "input.jpg", "input image")
+	out := fs.String("out", "output.png", "output image (.png, .gif or .jpg)")
+	colors := fs.Int("colors", 16, "number of palette colors (1..256)")
+	method := fs.String("method", "mediancut", "quantization method: mediancut, octree or kmeans")
+	palette := fs.String("palette", "", "fixed palette as comma-separated #rrggbb colors (overrides -colors and -method)")
+	dither := fs.Bool("dither", false, "apply Floyd-Steinberg dithering")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	img, err := LoadImage(*in)
+	if err != nil {
+		return err
+	}
+	var result *image.Paletted
+	if *palette != "" {
+		p, err := quantize.ParsePalette(*palette)
+		if err != nil {
+			return err
+		}
+		result = quantize.MapToPalette(img, p, *dither)
+	} else {
+		result, err = quantize.Quantize(img, *colors, quantize.Method(*method), *dither)
+		if err != nil {
+			return err
+		}
+	}
+	return saveOutput(result, *out)
+}
+
+// parseChannels splits a comma-separated channel list
+func parseChannels(list string) []histogram.Channel {
+	if list == "all" {
+		return histogram.AllChannels
+	}
+	channels := []histogram.Channel{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			channels = append(channels, histogram.Channel(strings.ToLower(name)))
+		}
+	}
+	return channels
+}
+
+// saveOutput picks the format from the file extension: .png, .gif, otherwise JPEG
+func saveOutput(img image.Image, filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		return withOutput(filename, func(w io.Writer) error { return png.Encode(w, img) })
+	case ".gif":
+		return withOutput(filename, func(w io.Writer) error { return gif.Encode(w, img, nil) })
+	default:
+		return SaveImage(img, filename)
+	}
+}
+
+// withOutput calls write with the given file, or stdout when filename is empty
+func withOutput(filename string, write func(w io.Writer) error) error {
+	if filename == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
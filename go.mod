@@ -2,6 +2,11 @@ module image-processing/v1
 
 go 1.23.4
 
+require (
+	golang.org/x/image v0.25.0
+	gonum.org/v1/plot v0.16.0
+)
+
 require (
 	codeberg.org/go-fonts/liberation v0.5.0 // indirect
 	codeberg.org/go-latex/latex v0.1.0 // indirect
@@ -11,8 +16,6 @@ require (
 	github.com/campoy/embedmd v1.0.0 // indirect
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/image v0.25.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
-	gonum.org/v1/plot v0.16.0 // indirect
 	rsc.io/pdf v0.1.1 // indirect
 )
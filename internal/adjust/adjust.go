@@ -0,0 +1,199 @@
+// Package adjust zawiera korekty tonalne: jasność, kontrast, gamma, ekspozycja, poziomy
+// i automatyczne rozciąganie zakresu. Każda korekta to tablica przejścia 256 wartości.
+// Alfa: tablice są stosowane do koloru bez premultiplikacji, kanał alfa zostaje zachowany.
+package adjust
+
+import (
+	"fmt"
+	"image"
+	"image-processing/v1/internal/histogram"
+	"image-processing/v1/internal/srgb"
+	"image/color"
+	"math"
+)
+
+// LUT - tablica przejścia: nowa wartość dla każdej wartości 0..255
+type LUT [256]uint8
+
+// Levels - parametry korekty poziomów, jak w programach graficznych
+type Levels struct {
+	InBlack, InWhite   uint8   // wartości wejściowe mapowane na czerń i biel
+	Gamma              float64 // gamma środkowych tonów (1 - bez zmian, > 1 rozjaśnia)
+	OutBlack, OutWhite uint8   // zakres wyjściowy
+}
+
+// Identity - tablica niezmieniająca wartości
+func Identity() LUT {
+	var lut LUT
+	for i := range lut {
+		lut[i] = uint8(i)
+	}
+	return lut
+}
+
+// Then składa tablice: najpierw l, potem next
+func (l LUT) Then(next LUT) LUT {
+	var out LUT
+	for i, v := range l {
+		out[i] = next[v]
+	}
+	return out
+}
+
+// BrightnessLUT przesuwa wartości o delta (-255..255)
+func BrightnessLUT(delta int) LUT {
+	return fromFunc(func(v float64) float64 { return v + float64(delta) })
+}
+
+// ContrastLUT skaluje odległość od środka zakresu (128) o factor; 1 - bez zmian, 0 - jednolita szarość
+func ContrastLUT(factor float64) LUT {
+	return fromFunc(func(v float64) float64 { return (v-128)*factor + 128 })
+}
+
+// GammaLUT - korekta gamma: v' = 255 * (v/255)^(1/gamma). gamma > 1 rozjaśnia średnie tony.
+// gamma <= 0 (lub NaN) jest traktowana jak 1, tak jak w LevelsLUT.
+func GammaLUT(gamma float64) LUT {
+	if !(gamma > 0) {
+		gamma = 1
+	}
+	return fromFunc(func(v float64) float64 { return 255 * math.Pow(v/255, 1/gamma) })
+}
+
+// ExposureLUT zmienia ekspozycję o stops przysłon; mnożenie odbywa się w świetle liniowym
+func ExposureLUT(stops float64) LUT {
+	var lut LUT
+	factor := math.Exp2(stops)
+	for i := range lut {
+		lut[i] = srgb.FromLinear(srgb.ToLinear(uint8(i)) * factor)
+	}
+	return lut
+}
+
+// LevelsLUT buduje tablicę korekty poziomów
+func LevelsLUT(l Levels) LUT {
+	gamma := l.Gamma
+	if gamma <= 0 {
+		gamma = 1
+	}
+	inRange := math.Max(1, float64(l.InWhite)-float64(l.InBlack))
+	outRange := float64(l.OutWhite) - float64(l.OutBlack)
+	return fromFunc(func(v float64) float64 {
+		t := math.Max(0, math.Min(1, (v-float64(l.InBlack))/inRange))
+		return float64(l.OutBlack) + math.Pow(t, 1/gamma)*outRange
+	})
+}
+
+// StretchLUT rozciąga zakres low..high na pełne 0..255
+func StretchLUT(low, high uint8) LUT {
+	if high <= low {
+		return Identity()
+	}
+	return LevelsLUT(Levels{InBlack: low, InWhite: high, Gamma: 1, OutBlack: 0, OutWhite: 255})
+}
+
+// Apply stosuje tę samą tablicę do kanałów R, G i B
+func Apply(img image.Image, lut LUT) *image.RGBA {
+	return ApplyChannels(img, lut, lut, lut)
+}
+
+// ApplyChannels stosuje osobne tablice do kanałów R, G i B
+func ApplyChannels(img image.Image, r, g, b LUT) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			out.Set(x, y, color.NRGBA{R: r[c.R], G: g[c.G], B: b[c.B], A: c.A})
+		}
+	}
+	return out
+}
+
+// Brightness przesuwa jasność obrazu o delta (-255..255)
+func Brightness(img image.Image, delta int) *image.RGBA {
+	return Apply(img, BrightnessLUT(delta))
+}
+
+// Contrast zmienia kontrast obrazu; factor 1 - bez zmian
+func Contrast(img image.Image, factor float64) *image.RGBA {
+	return Apply(img, ContrastLUT(factor))
+}
+
+// Gamma stosuje korekcję gamma
+func Gamma(img image.Image, gamma float64) *image.RGBA {
+	return Apply(img, GammaLUT(gamma))
+}
+
+// Exposure zmienia ekspozycję o stops przysłon (dodatnie rozjaśniają)
+func Exposure(img image.Image, stops float64) *image.RGBA {
+	return Apply(img, ExposureLUT(stops))
+}
+
+// ApplyLevels stosuje korektę poziomów
+func ApplyLevels(img image.Image, l Levels) *image.RGBA {
+	return Apply(img, LevelsLUT(l))
+}
+
+// AutoLevels rozciąga każdy kanał R, G, B osobno między percentylami clip i 100-clip (w procentach).
+// Usuwa też zafarb, bo kanały są rozciągane niezależnie.
+func AutoLevels(img image.Image, clip float64) (*image.RGBA, error) {
+	if err := checkClip(clip); err != nil {
+		return nil, err
+	}
+	hists, err := histogram.ComputeAll(img, []histogram.Channel{histogram.ChannelR, histogram.ChannelG, histogram.ChannelB}, 256)
+	if err != nil {
+		return nil, err
+	}
+	var luts [3]LUT
+	for i, h := range hists {
+		low, high := clipRange(h, clip)
+		luts[i] = StretchLUT(low, high)
+	}
+	return ApplyChannels(img, luts[0], luts[1], luts[2]), nil
+}
+
+// AutoContrast rozciąga zakres jasności między percentylami clip i 100-clip;
+// ta sama tablica jest stosowana do wszystkich kanałów, więc barwy nie ulegają przesunięciu
+func AutoContrast(img image.Image, clip float64) (*image.RGBA, error) {
+	if err := checkClip(clip); err != nil {
+		return nil, err
+	}
+	h, err := histogram.Compute(img, histogram.ChannelLuminance, 256)
+	if err != nil {
+		return nil, err
+	}
+	low, high := clipRange(h, clip)
+	return Apply(img, StretchLUT(low, high)), nil
+}
+
+// clipRange zwraca wartości, poniżej i powyżej których leży po clip procent pikseli
+func clipRange(h histogram.Histogram, clip float64) (uint8, uint8) {
+	limit := clip / 100 * float64(h.Total)
+	low, sum := 0, 0
+	for low < 255 && float64(sum+h.Counts[low]) <= limit {
+		sum += h.Counts[low]
+		low++
+	}
+	high, sum := 255, 0
+	for high > 0 && float64(sum+h.Counts[high]) <= limit {
+		sum += h.Counts[high]
+		high--
+	}
+	return uint8(low), uint8(high)
+}
+
+func checkClip(clip float64) error {
+	if clip < 0 || clip >= 50 {
+		return fmt.Errorf("clip percentage must be in [0, 50), got %g", clip)
+	}
+	return nil
+}
+
+// fromFunc buduje tablicę z funkcji, zaokrąglając i przycinając wynik do 0..255
+func fromFunc(f func(v float64) float64) LUT {
+	var lut LUT
+	for i := range lut {
+		lut[i] = uint8(math.Max(0, math.Min(255, math.Round(f(float64(i))))))
+	}
+	return lut
+}
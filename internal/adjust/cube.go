@@ -0,0 +1,289 @@
+package adjust
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image-processing/v1/internal/clamp"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type CubeInterpolation string
+
+const (
+	Trilinear   CubeInterpolation = "trilinear"   // 8 narożników komórki
+	Tetrahedral CubeInterpolation = "tetrahedral" // 4 narożniki czworościanu, lepiej zachowuje szarości
+)
+
+// Cube - tablica LUT w formacie .cube (Adobe/Resolve), jednowymiarowa lub trójwymiarowa.
+// Wartości są w zakresie 0..1. W tablicy 3D indeks R zmienia się najszybciej:
+// Table[r + g*Size + b*Size*Size]; w tablicy 1D Table[i] zawiera wyjścia R, G, B dla wejścia i.
+type Cube struct {
+	Title     string
+	Dimension int // 1 lub 3
+	Size      int
+	DomainMin [3]float64
+	DomainMax [3]float64
+	Table     [][3]float64
+}
+
+// NewCube3D buduje tablicę 3D o podanym rozmiarze (co najmniej 2) z funkcji przekształcającej kolor (wartości 0..1)
+func NewCube3D(size int, f func(r, g, b float64) (float64, float64, float64)) (*Cube, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("invalid LUT size %d: must be at least 2", size)
+	}
+	cube := &Cube{Dimension: 3, Size: size, DomainMax: [3]float64{1, 1, 1}, Table: make([][3]float64, size*size*size)}
+	step := 1 / float64(size-1)
+	for b := 0; b < size; b++ {
+		for g := 0; g < size; g++ {
+			for r := 0; r < size; r++ {
+				or, og, ob := f(float64(r)*step, float64(g)*step, float64(b)*step)
+				cube.Table[r+g*size+b*size*size] = [3]float64{or, og, ob}
+			}
+		}
+	}
+	return cube, nil
+}
+
+// LoadCube wczytuje plik .cube
+func LoadCube(filename string) (*Cube, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseCube(file)
+}
+
+// ParseCube odczytuje tablicę w formacie .cube
+func ParseCube(r io.Reader) (*Cube, error) {
+	cube := &Cube{DomainMax: [3]float64{1, 1, 1}}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		switch fields[0] {
+		case "TITLE":
+			cube.Title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(text, "TITLE")), `"`)
+		case "LUT_1D_SIZE", "LUT_3D_SIZE":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: %s expects one value", line, fields[0])
+			}
+			size, err := strconv.Atoi(fields[1])
+			if err != nil || size < 2 {
+				return nil, fmt.Errorf("line %d: invalid LUT size %q", line, fields[1])
+			}
+			cube.Size = size
+			cube.Dimension = 1
+			if fields[0] == "LUT_3D_SIZE" {
+				cube.Dimension = 3
+			}
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			v, err := parseTriple(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if fields[0] == "DOMAIN_MIN" {
+				cube.DomainMin = v
+			} else {
+				cube.DomainMax = v
+			}
+		case "LUT_1D_INPUT_RANGE", "LUT_3D_INPUT_RANGE":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: %s expects two values", line, fields[0])
+			}
+			lo, err1 := strconv.ParseFloat(fields[1], 64)
+			hi, err2 := strconv.ParseFloat(fields[2], 64)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid input range", line)
+			}
+			cube.DomainMin = [3]float64{lo, lo, lo}
+			cube.DomainMax = [3]float64{hi, hi, hi}
+		default:
+			v, err := parseTriple(fields)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			cube.Table = append(cube.Table, v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if cube.Dimension == 0 {
+		return nil, fmt.Errorf("missing LUT_1D_SIZE or LUT_3D_SIZE")
+	}
+	want := cube.Size
+	if cube.Dimension == 3 {
+		want = cube.Size * cube.Size * cube.Size
+	}
+	if len(cube.Table) != want {
+		return nil, fmt.Errorf("expected %d table entries, got %d", want, len(cube.Table))
+	}
+	for c := 0; c < 3; c++ {
+		if cube.DomainMax[c] <= cube.DomainMin[c] {
+			return nil, fmt.Errorf("invalid domain for channel %d: %g..%g", c, cube.DomainMin[c], cube.DomainMax[c])
+		}
+	}
+	return cube, nil
+}
+
+func parseTriple(fields []string) ([3]float64, error) {
+	var v [3]float64
+	if len(fields) != 3 {
+		return v, fmt.Errorf("expected 3 values, got %d", len(fields))
+	}
+	for i, f := range fields {
+		x, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid number %q", f)
+		}
+		v[i] = x
+	}
+	return v, nil
+}
+
+// WriteCube zapisuje tablicę w formacie .cube
+func WriteCube(w io.Writer, cube *Cube) error {
+	bw := bufio.NewWriter(w)
+	if cube.Title != "" {
+		fmt.Fprintf(bw, "TITLE %q\n", cube.Title)
+	}
+	fmt.Fprintf(bw, "LUT_%dD_SIZE %d\n", cube.Dimension, cube.Size)
+	fmt.Fprintf(bw, "DOMAIN_MIN %g %g %g\n", cube.DomainMin[0], cube.DomainMin[1], cube.DomainMin[2])
+	fmt.Fprintf(bw, "DOMAIN_MAX %g %g %g\n", cube.DomainMax[0], cube.DomainMax[1], cube.DomainMax[2])
+	for _, v := range cube.Table {
+		fmt.Fprintf(bw, "%.6f %.6f %.6f\n", v[0], v[1], v[2])
+	}
+	return bw.Flush()
+}
+
+// Apply stosuje tablicę do obrazu. Dla tablic 1D interpolacja jest zawsze liniowa,
+// a method dotyczy tylko tablic 3D.
+func (cube *Cube) Apply(img image.Image, method CubeInterpolation) (*image.RGBA, error) {
+	if cube.Dimension == 1 {
+		var luts [3]LUT
+		for c := range luts {
+			for v := range luts[c] {
+				luts[c][v] = clamp.UnitToByte(cube.lookup1D(c, float64(v)/255))
+			}
+		}
+		return ApplyChannels(img, luts[0], luts[1], luts[2]), nil
+	}
+
+	var lookup func(r, g, b float64) [3]float64
+	switch method {
+	case Trilinear:
+		lookup = cube.trilinear
+	case Tetrahedral:
+		lookup = cube.tetrahedral
+	default:
+		return nil, fmt.Errorf("unknown interpolation %q", method)
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			v := lookup(cube.position(0, c.R), cube.position(1, c.G), cube.position(2, c.B))
+			out.Set(x, y, color.NRGBA{R: clamp.UnitToByte(v[0]), G: clamp.UnitToByte(v[1]), B: clamp.UnitToByte(v[2]), A: c.A})
+		}
+	}
+	return out, nil
+}
+
+// position - położenie wartości 8-bitowej kanału c w siatce tablicy (0..Size-1)
+func (cube *Cube) position(c int, v uint8) float64 {
+	t := (float64(v)/255 - cube.DomainMin[c]) / (cube.DomainMax[c] - cube.DomainMin[c])
+	return math.Max(0, math.Min(1, t)) * float64(cube.Size-1)
+}
+
+// lookup1D - liniowa interpolacja kanału c tablicy 1D dla wartości v (0..1)
+func (cube *Cube) lookup1D(c int, v float64) float64 {
+	t := (v - cube.DomainMin[c]) / (cube.DomainMax[c] - cube.DomainMin[c])
+	pos := math.Max(0, math.Min(1, t)) * float64(cube.Size-1)
+	i := min(int(pos), cube.Size-2)
+	f := pos - float64(i)
+	return (1-f)*cube.Table[i][c] + f*cube.Table[i+1][c]
+}
+
+// cell zwraca indeks dolnego narożnika komórki i położenie wewnątrz niej
+func (cube *Cube) cell(pos float64) (int, float64) {
+	i := min(int(pos), cube.Size-2)
+	return i, pos - float64(i)
+}
+
+// corner - wartość narożnika komórki (r, g, b) przesuniętego o dr, dg, db
+func (cube *Cube) corner(r, g, b, dr, dg, db int) [3]float64 {
+	n := cube.Size
+	return cube.Table[(r+dr)+(g+dg)*n+(b+db)*n*n]
+}
+
+func (cube *Cube) trilinear(pr, pg, pb float64) [3]float64 {
+	r, fr := cube.cell(pr)
+	g, fg := cube.cell(pg)
+	b, fb := cube.cell(pb)
+	var out [3]float64
+	for i := 0; i < 8; i++ {
+		dr, dg, db := i&1, (i>>1)&1, (i>>2)&1
+		w := weight(fr, dr) * weight(fg, dg) * weight(fb, db)
+		c := cube.corner(r, g, b, dr, dg, db)
+		for k := range out {
+			out[k] += w * c[k]
+		}
+	}
+	return out
+}
+
+func weight(f float64, d int) float64 {
+	if d == 1 {
+		return f
+	}
+	return 1 - f
+}
+
+// tetrahedral dzieli komórkę na 6 czworościanów wzdłuż przekątnej czerń-biel
+// i interpoluje w tym, który zawiera punkt
+func (cube *Cube) tetrahedral(pr, pg, pb float64) [3]float64 {
+	r, fr := cube.cell(pr)
+	g, fg := cube.cell(pg)
+	b, fb := cube.cell(pb)
+	c000 := cube.corner(r, g, b, 0, 0, 0)
+	c111 := cube.corner(r, g, b, 1, 1, 1)
+
+	// Kolejność osi od największego do najmniejszego przesunięcia wyznacza drogę 000 -> 111
+	var first, second [3]int
+	var w1, w2, w3 float64
+	switch {
+	case fr >= fg && fg >= fb:
+		first, second, w1, w2, w3 = [3]int{1, 0, 0}, [3]int{1, 1, 0}, fr, fg, fb
+	case fr >= fb && fb >= fg:
+		first, second, w1, w2, w3 = [3]int{1, 0, 0}, [3]int{1, 0, 1}, fr, fb, fg
+	case fb >= fr && fr >= fg:
+		first, second, w1, w2, w3 = [3]int{0, 0, 1}, [3]int{1, 0, 1}, fb, fr, fg
+	case fg >= fr && fr >= fb:
+		first, second, w1, w2, w3 = [3]int{0, 1, 0}, [3]int{1, 1, 0}, fg, fr, fb
+	case fg >= fb && fb >= fr:
+		first, second, w1, w2, w3 = [3]int{0, 1, 0}, [3]int{0, 1, 1}, fg, fb, fr
+	default: // fb >= fg >= fr
+		first, second, w1, w2, w3 = [3]int{0, 0, 1}, [3]int{0, 1, 1}, fb, fg, fr
+	}
+	c1 := cube.corner(r, g, b, first[0], first[1], first[2])
+	c2 := cube.corner(r, g, b, second[0], second[1], second[2])
+	var out [3]float64
+	for k := range out {
+		out[k] = (1-w1)*c000[k] + (w1-w2)*c1[k] + (w2-w3)*c2[k] + w3*c111[k]
+	}
+	return out
+}
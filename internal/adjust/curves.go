@@ -0,0 +1,117 @@
+package adjust
+
+import (
+	"fmt"
+	"image"
+	"image-processing/v1/internal/histogram"
+	"math"
+	"sort"
+)
+
+// Point - punkt kontrolny krzywej tonalnej (wejście X i wyjście Y w zakresie 0..255)
+type Point struct {
+	X, Y float64
+}
+
+type CurveChannel string
+
+const (
+	CurveRGB       CurveChannel = "rgb"       // ta sama krzywa dla R, G i B
+	CurveR         CurveChannel = "r"         // tylko kanał R
+	CurveG         CurveChannel = "g"         // tylko kanał G
+	CurveB         CurveChannel = "b"         // tylko kanał B
+	CurveLuminance CurveChannel = "luminance" // krzywa jasności, kolor przesuwany o tę samą wartość
+)
+
+// CurveLUT buduje tablicę z krzywej przechodzącej przez punkty kontrolne.
+// Używany jest monotoniczny spline Hermite'a (Fritsch-Carlson), który w przeciwieństwie
+// do zwykłego splajnu sześciennego nie wychodzi poza wartości sąsiednich punktów.
+// Poniżej pierwszego i powyżej ostatniego punktu krzywa jest stała.
+func CurveLUT(points []Point) (LUT, error) {
+	if len(points) < 2 {
+		return LUT{}, fmt.Errorf("curve needs at least 2 control points, got %d", len(points))
+	}
+	pts := append([]Point(nil), points...)
+	sort.Slice(pts, func(i, j int) bool { return pts[i].X < pts[j].X })
+	for i := 1; i < len(pts); i++ {
+		if pts[i].X == pts[i-1].X {
+			return LUT{}, fmt.Errorf("duplicate control point at x=%g", pts[i].X)
+		}
+	}
+	tangents := monotoneTangents(pts)
+
+	return fromFunc(func(v float64) float64 {
+		if v <= pts[0].X {
+			return pts[0].Y
+		}
+		last := len(pts) - 1
+		if v >= pts[last].X {
+			return pts[last].Y
+		}
+		k := sort.Search(len(pts), func(i int) bool { return pts[i].X > v }) - 1
+		h := pts[k+1].X - pts[k].X
+		t := (v - pts[k].X) / h
+		t2, t3 := t*t, t*t*t
+		return (2*t3-3*t2+1)*pts[k].Y + (t3-2*t2+t)*h*tangents[k] +
+			(-2*t3+3*t2)*pts[k+1].Y + (t3-t2)*h*tangents[k+1]
+	}), nil
+}
+
+// monotoneTangents - styczne w punktach kontrolnych wg metody Fritscha-Carlsona
+func monotoneTangents(pts []Point) []float64 {
+	n := len(pts)
+	slopes := make([]float64, n-1)
+	for i := range slopes {
+		slopes[i] = (pts[i+1].Y - pts[i].Y) / (pts[i+1].X - pts[i].X)
+	}
+	tangents := make([]float64, n)
+	tangents[0], tangents[n-1] = slopes[0], slopes[n-2]
+	for i := 1; i < n-1; i++ {
+		if slopes[i-1]*slopes[i] <= 0 {
+			tangents[i] = 0
+		} else {
+			tangents[i] = (slopes[i-1] + slopes[i]) / 2
+		}
+	}
+	for i, s := range slopes {
+		if s == 0 {
+			tangents[i], tangents[i+1] = 0, 0
+			continue
+		}
+		a, b := tangents[i]/s, tangents[i+1]/s
+		if r := a*a + b*b; r > 9 {
+			f := 3 / math.Sqrt(r)
+			tangents[i], tangents[i+1] = f*a*s, f*b*s
+		}
+	}
+	return tangents
+}
+
+// ApplyCurve stosuje krzywą tonalną do wybranego kanału (lub do jasności)
+func ApplyCurve(img image.Image, points []Point, channel CurveChannel) (*image.RGBA, error) {
+	lut, err := CurveLUT(points)
+	if err != nil {
+		return nil, err
+	}
+	id := Identity()
+	switch channel {
+	case CurveRGB:
+		return Apply(img, lut), nil
+	case CurveR:
+		return ApplyChannels(img, lut, id, id), nil
+	case CurveG:
+		return ApplyChannels(img, id, lut, id), nil
+	case CurveB:
+		return ApplyChannels(img, id, id, lut), nil
+	case CurveLuminance:
+		return ApplyLuminance(img, lut), nil
+	default:
+		return nil, fmt.Errorf("unknown curve channel %q", channel)
+	}
+}
+
+// ApplyLuminance stosuje tablicę do jasności piksela i przesuwa R, G, B o tę samą wartość
+// (ta sama jasność co przy dopasowaniu histogramu, zob. histogram.ShiftLuminance)
+func ApplyLuminance(img image.Image, lut LUT) *image.RGBA {
+	return histogram.ShiftLuminance(img, lut)
+}
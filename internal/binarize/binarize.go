@@ -1,3 +1,5 @@
+// Package binarize progowo zamienia obraz na czarno-biały, opcjonalnie z ditheringiem (pakiet dither).
+// Alfa: próg porównywany jest z jasnością koloru bez premultiplikacji; wynik (image.Gray) nie ma alfy.
 package binarize
 
 import (
@@ -6,8 +8,13 @@ import (
 	"image-processing/v1/internal/grayscale"
 )
 
+// luminance zwraca jasność porównywaną z progiem; wspólna dla progowania z ditheringiem i bez
+func luminance(r, g, b uint8) uint8 {
+    return grayscale.ConvertToGrayscale(r, g, b)
+}
+
 func BinarizeColor(r, g, b uint8, threshold uint8) uint8 {
-    gray := grayscale.ConvertToGrayscale(r, g, b)
+    gray := luminance(r, g, b)
     if gray >= threshold {
         return 1 // Bright pixel
     }
@@ -20,8 +27,8 @@ func ApplyBinarizationToImage(img image.Image, threshold uint8) *image.Gray {
 
     for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
         for x := bounds.Min.X; x < bounds.Max.X; x++ {
-            r, g, b, _ := img.At(x, y).RGBA()
-            binaryValue := BinarizeColor(uint8(r>>8), uint8(g>>8), uint8(b>>8), threshold)
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            binaryValue := BinarizeColor(c.R, c.G, c.B, threshold)
             if binaryValue == 1 {
                 binaryImg.SetGray(x, y, color.Gray{Y: 255}) // White
             } else {
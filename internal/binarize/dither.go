@@ -0,0 +1,41 @@
+package binarize
+
+import (
+    "image"
+    "image-processing/v1/internal/dither"
+    "image/color"
+)
+
+// ApplyDitheredBinarization zamienia obraz na czarno-biały, rozpraszając błąd wybraną metodą,
+// dzięki czemu półtony są oddane gęstością czarnych i białych pikseli.
+// Piksel jest biały, gdy jego jasność (ta sama co w BinarizeColor, z rozproszonym błędem) jest >= threshold.
+func ApplyDitheredBinarization(img image.Image, threshold uint8, opts dither.Options) (*image.Gray, error) {
+    bounds := img.Bounds()
+    plane := make([][]float64, bounds.Dy())
+    for y := range plane {
+        plane[y] = make([]float64, bounds.Dx())
+        for x := range plane[y] {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            plane[y][x] = float64(luminance(c.R, c.G, c.B))
+        }
+    }
+
+    quantize := func(v float64) float64 {
+        if v >= float64(threshold) {
+            return 255
+        }
+        return 0
+    }
+    result, err := dither.Apply(plane, quantize, 255, opts)
+    if err != nil {
+        return nil, err
+    }
+
+    out := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y, row := range result {
+        for x, v := range row {
+            out.SetGray(x, y, color.Gray{Y: uint8(v)})
+        }
+    }
+    return out, nil
+}
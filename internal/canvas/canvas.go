@@ -0,0 +1,115 @@
+// Package canvas zmienia obszar obrazu: wycina fragmenty, obcina jednolite brzegi i dodaje ramki.
+// Alfa: piksele są kopiowane bez zmian, razem z kanałem alfa.
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type BorderType string
+
+const (
+	Constant  BorderType = "constant"  // ramka w jednolitym kolorze
+	Replicate BorderType = "replicate" // powielenie skrajnych pikseli
+	Reflect   BorderType = "reflect"   // odbicie lustrzane bez powtarzania skrajnego piksela
+)
+
+// Crop wycina prostokąt rect (we współrzędnych obrazu, przycięty do jego granic)
+func Crop(img image.Image, rect image.Rectangle) *image.RGBA {
+	rect = rect.Intersect(img.Bounds())
+	out := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(out, out.Bounds(), img, rect.Min, draw.Src)
+	return out
+}
+
+// CropPercent wycina fragment podany w procentach rozmiaru obrazu (0..100)
+func CropPercent(img image.Image, left, top, width, height float64) *image.RGBA {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	x0 := bounds.Min.X + int(math.Round(w*left/100))
+	y0 := bounds.Min.Y + int(math.Round(h*top/100))
+	x1 := x0 + int(math.Round(w*width/100))
+	y1 := y0 + int(math.Round(h*height/100))
+	return Crop(img, image.Rect(x0, y0, x1, y1))
+}
+
+// Trim obcina jednolite brzegi w kolorze lewego górnego piksela.
+// tolerance - największa różnica na dowolnym kanale, przy której piksel uznawany jest za tło.
+// Gdy cały obraz jest jednolity, zwracana jest jego niezmieniona kopia.
+func Trim(img image.Image, tolerance uint8) *image.RGBA {
+	bounds := img.Bounds()
+	ref := color.NRGBAModel.Convert(img.At(bounds.Min.X, bounds.Min.Y)).(color.NRGBA)
+	content := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if differs(c.R, ref.R, tolerance) || differs(c.G, ref.G, tolerance) ||
+				differs(c.B, ref.B, tolerance) || differs(c.A, ref.A, tolerance) {
+				content = content.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if content.Empty() {
+		return Crop(img, bounds)
+	}
+	return Crop(img, content)
+}
+
+// Pad dodaje ramkę o podanych (nieujemnych) szerokościach. fill jest używany tylko dla ramki Constant
+// (nil = przezroczysta).
+func Pad(img image.Image, top, right, bottom, left int, border BorderType, fill color.Color) (*image.RGBA, error) {
+	if top < 0 || right < 0 || bottom < 0 || left < 0 {
+		return nil, fmt.Errorf("invalid padding %d, %d, %d, %d: widths must not be negative", top, right, bottom, left)
+	}
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	out := image.NewRGBA(image.Rect(0, 0, w+left+right, h+top+bottom))
+	if border == Constant {
+		if fill != nil {
+			draw.Draw(out, out.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+		}
+		draw.Draw(out, image.Rect(left, top, left+w, top+h), img, bounds.Min, draw.Src)
+		return out, nil
+	}
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		sy := borderIndex(y-top, h, border)
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			sx := borderIndex(x-left, w, border)
+			out.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return out, nil
+}
+
+// borderIndex przelicza indeks spoza zakresu 0..n-1 na indeks piksela źródłowego
+func borderIndex(i, n int, border BorderType) int {
+	if i >= 0 && i < n {
+		return i
+	}
+	if border == Reflect && n > 1 {
+		period := 2 * (n - 1)
+		i %= period
+		if i < 0 {
+			i += period
+		}
+		if i >= n {
+			i = period - i
+		}
+		return i
+	}
+	if i < 0 {
+		return 0
+	}
+	return n - 1
+}
+
+func differs(a, b, tolerance uint8) bool {
+	if a > b {
+		return a-b > tolerance
+	}
+	return b-a > tolerance
+}
@@ -0,0 +1,166 @@
+// Package channel rozdziela obraz na kanały, składa kanały z powrotem, zamienia ich kolejność
+// i wyodrębnia kanał alfa jako maskę.
+// Alfa: kanały R, G, B są brane bez premultiplikacji, alfa jest osobnym kanałem.
+package channel
+
+import (
+	"fmt"
+	"image"
+	"image-processing/v1/internal/clamp"
+	"image-processing/v1/internal/hsl"
+	"image/color"
+	"strings"
+)
+
+// Split rozdziela obraz na kanały R, G, B i A zapisane jako obrazy w skali szarości
+func Split(img image.Image) [4]*image.Gray {
+	bounds := img.Bounds()
+	var out [4]*image.Gray
+	for i := range out {
+		out[i] = image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			for i, v := range [4]uint8{c.R, c.G, c.B, c.A} {
+				out[i].SetGray(x, y, color.Gray{Y: v})
+			}
+		}
+	}
+	return out
+}
+
+// Merge składa obraz z kanałów R, G, B i opcjonalnego A (nil - obraz nieprzezroczysty).
+// Kanały muszą mieć ten sam rozmiar; wartości są odczytywane jako jasność.
+func Merge(r, g, b, a image.Image) (*image.RGBA, error) {
+	if err := sameSize(r, g, b, a); err != nil {
+		return nil, err
+	}
+	bounds := r.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBA{R: grayAt(r, x, y), G: grayAt(g, x, y), B: grayAt(b, x, y), A: 255}
+			if a != nil {
+				c.A = grayAt(a, x, y)
+			}
+			out.Set(x, y, c)
+		}
+	}
+	return out, nil
+}
+
+// SplitHSL rozdziela obraz na składowe H, S i L (pakiet hsl) przeskalowane do 0..255
+func SplitHSL(img image.Image) [3]*image.Gray {
+	bounds := img.Bounds()
+	var out [3]*image.Gray
+	for i := range out {
+		out[i] = image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			h, s, l := hsl.RGBToHSL(c.R, c.G, c.B)
+			out[0].SetGray(x, y, color.Gray{Y: clamp.UnitToByte(h / 360)})
+			out[1].SetGray(x, y, color.Gray{Y: clamp.UnitToByte(s)})
+			out[2].SetGray(x, y, color.Gray{Y: clamp.UnitToByte(l)})
+		}
+	}
+	return out
+}
+
+// MergeHSL składa nieprzezroczysty obraz RGB ze składowych H, S i L zapisanych jak w SplitHSL
+func MergeHSL(h, s, l image.Image) (*image.RGBA, error) {
+	if err := sameSize(h, s, l, nil); err != nil {
+		return nil, err
+	}
+	bounds := h.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			hue := float64(grayAt(h, x, y)) / 255 * 360
+			if hue >= 360 {
+				hue = 0
+			}
+			r, g, b := hsl.HSLToRGB(hue, float64(grayAt(s, x, y))/255, float64(grayAt(l, x, y))/255)
+			out.SetRGBA(x, y, color.RGBA{R: r, G: g, B: b, A: 255})
+		}
+	}
+	return out, nil
+}
+
+// Swap zmienia kolejność kanałów. order to 3 lub 4 litery z "rgba" mówiące, skąd brać kolejne
+// kanały wyniku, np. "bgr" zamienia R z B, "rrr" powiela kanał R, "bgra" zachowuje alfę.
+// Przy 3 literach alfa zostaje bez zmian.
+func Swap(img image.Image, order string) (*image.RGBA, error) {
+	order = strings.ToLower(order)
+	if len(order) != 3 && len(order) != 4 {
+		return nil, fmt.Errorf("channel order must have 3 or 4 letters, got %q", order)
+	}
+	src := [4]int{0, 1, 2, 3}
+	for i, ch := range order {
+		idx := strings.IndexRune("rgba", ch)
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown channel %q in order %q", ch, order)
+		}
+		src[i] = idx
+	}
+
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			v := [4]uint8{c.R, c.G, c.B, c.A}
+			out.Set(x, y, color.NRGBA{R: v[src[0]], G: v[src[1]], B: v[src[2]], A: v[src[3]]})
+		}
+	}
+	return out, nil
+}
+
+// ExtractAlpha zwraca kanał alfa jako maskę (np. dla draw.DrawMask)
+func ExtractAlpha(img image.Image) *image.Alpha {
+	bounds := img.Bounds()
+	out := image.NewAlpha(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			_, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			out.SetAlpha(x, y, color.Alpha{A: uint8(a >> 8)})
+		}
+	}
+	return out
+}
+
+// SetAlpha zastępuje kanał alfa obrazu jasnością maski (np. obraz z ExtractAlpha lub w skali szarości)
+func SetAlpha(img, mask image.Image) (*image.RGBA, error) {
+	if img.Bounds().Size() != mask.Bounds().Size() {
+		return nil, fmt.Errorf("mask size %v differs from image size %v", mask.Bounds().Size(), img.Bounds().Size())
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			c.A = grayAt(mask, x, y)
+			out.Set(x, y, c)
+		}
+	}
+	return out, nil
+}
+
+// grayAt - jasność piksela (x, y) liczona względem lewego górnego rogu obrazu
+func grayAt(img image.Image, x, y int) uint8 {
+	min := img.Bounds().Min
+	return color.GrayModel.Convert(img.At(min.X+x, min.Y+y)).(color.Gray).Y
+}
+
+// sameSize sprawdza, czy wszystkie kanały (poza nil) mają ten sam rozmiar co pierwszy
+func sameSize(first image.Image, others ...image.Image) error {
+	size := first.Bounds().Size()
+	for _, img := range others {
+		if img != nil && img.Bounds().Size() != size {
+			return fmt.Errorf("channel size %v differs from %v", img.Bounds().Size(), size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+// Package clamp przycina wartości zmiennoprzecinkowe do zakresu bajtu.
+package clamp
+
+import "math"
+
+// UnitToByte zamienia wartość 0..1 na 0..255 z zaokrągleniem i przycięciem
+func UnitToByte(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v*255))))
+}
@@ -0,0 +1,383 @@
+// Package colorspace przelicza kolory sRGB na inne przestrzenie barw: HSV, YCbCr (BT.601/BT.709),
+// liniowe RGB, CIE XYZ, CIE L*a*b*, LCh i Oklab, dla pojedynczych pikseli i całych obrazów.
+// Alfa: przeliczany jest kolor bez premultiplikacji; macierze kanałów nie przechowują alfy.
+package colorspace
+
+import (
+	"fmt"
+	"image"
+	"image-processing/v1/internal/clamp"
+	"image-processing/v1/internal/srgb"
+	"image/color"
+	"math"
+)
+
+type Space string
+
+const (
+	HSV      Space = "hsv"      // H 0..360, S 0..1, V 0..1
+	YCbCr601 Space = "ycbcr601" // Y, Cb, Cr 0..255 (pełny zakres, jak w JPEG)
+	YCbCr709 Space = "ycbcr709" // Y, Cb, Cr 0..255 (pełny zakres)
+	Linear   Space = "linear"   // liniowe R, G, B 0..1
+	XYZ      Space = "xyz"      // CIE XYZ (D65), Y bieli = 1
+	Lab      Space = "lab"      // L 0..100, a i b ok. -128..127
+	LCh      Space = "lch"      // L 0..100, C 0..~150, h 0..360
+	Oklab    Space = "oklab"    // L 0..1, a i b ok. -0.4..0.4
+)
+
+// Spaces - wszystkie obsługiwane przestrzenie
+var Spaces = []Space{HSV, YCbCr601, YCbCr709, Linear, XYZ, Lab, LCh, Oklab}
+
+// Biel odniesienia D65 (Y = 1)
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// RGBToHSV zamienia kolor sRGB na HSV
+func RGBToHSV(r, g, b uint8) (float64, float64, float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	max := math.Max(rf, math.Max(gf, bf))
+	min := math.Min(rf, math.Min(gf, bf))
+	delta := max - min
+
+	var h float64
+	switch {
+	case delta == 0:
+		h = 0
+	case max == rf:
+		h = math.Mod((gf-bf)/delta, 6)
+	case max == gf:
+		h = (bf-rf)/delta + 2
+	default:
+		h = (rf-gf)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	var s float64
+	if max > 0 {
+		s = delta / max
+	}
+	return h, s, max
+}
+
+// HSVToRGB zamienia HSV na sRGB
+func HSVToRGB(h, s, v float64) (uint8, uint8, uint8) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var rf, gf, bf float64
+	switch {
+	case h < 60:
+		rf, gf, bf = c, x, 0
+	case h < 120:
+		rf, gf, bf = x, c, 0
+	case h < 180:
+		rf, gf, bf = 0, c, x
+	case h < 240:
+		rf, gf, bf = 0, x, c
+	case h < 300:
+		rf, gf, bf = x, 0, c
+	default:
+		rf, gf, bf = c, 0, x
+	}
+	return clamp.UnitToByte(rf + m), clamp.UnitToByte(gf + m), clamp.UnitToByte(bf + m)
+}
+
+// lumaWeights zwraca wagi Kr i Kb dla standardu YCbCr
+func lumaWeights(space Space) (float64, float64) {
+	if space == YCbCr709 {
+		return 0.2126, 0.0722
+	}
+	return 0.299, 0.114
+}
+
+// RGBToYCbCr zamienia sRGB na YCbCr w pełnym zakresie 0..255; space to YCbCr601 lub YCbCr709
+func RGBToYCbCr(r, g, b uint8, space Space) (float64, float64, float64) {
+	kr, kb := lumaWeights(space)
+	rf, gf, bf := float64(r), float64(g), float64(b)
+	y := kr*rf + (1-kr-kb)*gf + kb*bf
+	cb := 128 + (bf-y)/(2*(1-kb))
+	cr := 128 + (rf-y)/(2*(1-kr))
+	return y, cb, cr
+}
+
+// YCbCrToRGB zamienia YCbCr (pełny zakres) na sRGB
+func YCbCrToRGB(y, cb, cr float64, space Space) (uint8, uint8, uint8) {
+	kr, kb := lumaWeights(space)
+	r := y + 2*(1-kr)*(cr-128)
+	b := y + 2*(1-kb)*(cb-128)
+	g := (y - kr*r - kb*b) / (1 - kr - kb)
+	return clamp.UnitToByte(r / 255), clamp.UnitToByte(g / 255), clamp.UnitToByte(b / 255)
+}
+
+// RGBToLinear zamienia sRGB na liniowe RGB (0..1)
+func RGBToLinear(r, g, b uint8) (float64, float64, float64) {
+	return srgb.ToLinear(r), srgb.ToLinear(g), srgb.ToLinear(b)
+}
+
+// LinearToRGB zamienia liniowe RGB na sRGB (wartości spoza 0..1 są przycinane)
+func LinearToRGB(r, g, b float64) (uint8, uint8, uint8) {
+	return srgb.FromLinear(r), srgb.FromLinear(g), srgb.FromLinear(b)
+}
+
+// RGBToXYZ zamienia sRGB na CIE XYZ (D65)
+func RGBToXYZ(r, g, b uint8) (float64, float64, float64) {
+	return linearToXYZ(RGBToLinear(r, g, b))
+}
+
+// XYZToRGB zamienia CIE XYZ (D65) na sRGB
+func XYZToRGB(x, y, z float64) (uint8, uint8, uint8) {
+	return LinearToRGB(xyzToLinear(x, y, z))
+}
+
+func linearToXYZ(r, g, b float64) (float64, float64, float64) {
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+	return x, y, z
+}
+
+func xyzToLinear(x, y, z float64) (float64, float64, float64) {
+	r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	b := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return r, g, b
+}
+
+// XYZToLab zamienia CIE XYZ na L*a*b* (biel D65)
+func XYZToLab(x, y, z float64) (float64, float64, float64) {
+	fx, fy, fz := labF(x/whiteX), labF(y/whiteY), labF(z/whiteZ)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// LabToXYZ zamienia L*a*b* na CIE XYZ (biel D65)
+func LabToXYZ(l, a, b float64) (float64, float64, float64) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	return whiteX * labFInv(fx), whiteY * labFInv(fy), whiteZ * labFInv(fz)
+}
+
+const labEpsilon = 6.0 / 29
+
+func labF(t float64) float64 {
+	if t > labEpsilon*labEpsilon*labEpsilon {
+		return math.Cbrt(t)
+	}
+	return t/(3*labEpsilon*labEpsilon) + 4.0/29
+}
+
+func labFInv(t float64) float64 {
+	if t > labEpsilon {
+		return t * t * t
+	}
+	return 3 * labEpsilon * labEpsilon * (t - 4.0/29)
+}
+
+// RGBToLab zamienia sRGB na L*a*b*
+func RGBToLab(r, g, b uint8) (float64, float64, float64) {
+	return XYZToLab(RGBToXYZ(r, g, b))
+}
+
+// LabToRGB zamienia L*a*b* na sRGB (kolory spoza gamutu są przycinane)
+func LabToRGB(l, a, b float64) (uint8, uint8, uint8) {
+	return XYZToRGB(LabToXYZ(l, a, b))
+}
+
+// Chroma, poniżej której kolor uznaje się za szary (błędy zaokrągleń bieli D65 są rzędu 1e-5)
+const achromaticChroma = 1e-4
+
+// LabToLCh zamienia L*a*b* na współrzędne biegunowe: jasność, chroma i odcień w stopniach.
+// Dla szarości odcień jest nieokreślony i zwracane jest 0, tak jak w RGBToHSV.
+func LabToLCh(l, a, b float64) (float64, float64, float64) {
+	c := math.Hypot(a, b)
+	if c < achromaticChroma {
+		return l, c, 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return l, c, h
+}
+
+// LChToLab zamienia LCh na L*a*b*
+func LChToLab(l, c, h float64) (float64, float64, float64) {
+	rad := h * math.Pi / 180
+	return l, c * math.Cos(rad), c * math.Sin(rad)
+}
+
+// RGBToLCh zamienia sRGB na LCh
+func RGBToLCh(r, g, b uint8) (float64, float64, float64) {
+	return LabToLCh(RGBToLab(r, g, b))
+}
+
+// LChToRGB zamienia LCh na sRGB
+func LChToRGB(l, c, h float64) (uint8, uint8, uint8) {
+	return LabToRGB(LChToLab(l, c, h))
+}
+
+// RGBToOklab zamienia sRGB na Oklab (B. Ottosson, 2020)
+func RGBToOklab(r, g, b uint8) (float64, float64, float64) {
+	lr, lg, lb := RGBToLinear(r, g, b)
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// OklabToRGB zamienia Oklab na sRGB
+func OklabToRGB(l, a, b float64) (uint8, uint8, uint8) {
+	l_ := l + 0.3963377774*a + 0.2158037573*b
+	m_ := l - 0.1055613458*a - 0.0638541728*b
+	s_ := l - 0.0894841775*a - 1.2914855480*b
+	l3, m3, s3 := l_*l_*l_, m_*m_*m_, s_*s_*s_
+	return LinearToRGB(
+		4.0767416621*l3-3.3077115913*m3+0.2309699292*s3,
+		-1.2684380046*l3+2.6097574011*m3-0.3413193965*s3,
+		-0.0041960863*l3-0.7034186147*m3+1.7076147010*s3,
+	)
+}
+
+// FromRGB zamienia kolor sRGB na współrzędne w przestrzeni space
+func FromRGB(r, g, b uint8, space Space) ([3]float64, error) {
+	var c0, c1, c2 float64
+	switch space {
+	case HSV:
+		c0, c1, c2 = RGBToHSV(r, g, b)
+	case YCbCr601, YCbCr709:
+		c0, c1, c2 = RGBToYCbCr(r, g, b, space)
+	case Linear:
+		c0, c1, c2 = RGBToLinear(r, g, b)
+	case XYZ:
+		c0, c1, c2 = RGBToXYZ(r, g, b)
+	case Lab:
+		c0, c1, c2 = RGBToLab(r, g, b)
+	case LCh:
+		c0, c1, c2 = RGBToLCh(r, g, b)
+	case Oklab:
+		c0, c1, c2 = RGBToOklab(r, g, b)
+	default:
+		return [3]float64{}, fmt.Errorf("unknown color space %q", space)
+	}
+	return [3]float64{c0, c1, c2}, nil
+}
+
+// ToRGB zamienia współrzędne w przestrzeni space na sRGB
+func ToRGB(c [3]float64, space Space) (uint8, uint8, uint8, error) {
+	var r, g, b uint8
+	switch space {
+	case HSV:
+		r, g, b = HSVToRGB(c[0], c[1], c[2])
+	case YCbCr601, YCbCr709:
+		r, g, b = YCbCrToRGB(c[0], c[1], c[2], space)
+	case Linear:
+		r, g, b = LinearToRGB(c[0], c[1], c[2])
+	case XYZ:
+		r, g, b = XYZToRGB(c[0], c[1], c[2])
+	case Lab:
+		r, g, b = LabToRGB(c[0], c[1], c[2])
+	case LCh:
+		r, g, b = LChToRGB(c[0], c[1], c[2])
+	case Oklab:
+		r, g, b = OklabToRGB(c[0], c[1], c[2])
+	default:
+		return 0, 0, 0, fmt.Errorf("unknown color space %q", space)
+	}
+	return r, g, b, nil
+}
+
+// Ranges zwraca typowy zakres [min, max] każdego z trzech kanałów przestrzeni,
+// używany przy zapisie kanałów jako obrazów w skali szarości
+func Ranges(space Space) ([3][2]float64, error) {
+	switch space {
+	case HSV:
+		return [3][2]float64{{0, 360}, {0, 1}, {0, 1}}, nil
+	case YCbCr601, YCbCr709:
+		return [3][2]float64{{0, 255}, {0, 255}, {0, 255}}, nil
+	case Linear:
+		return [3][2]float64{{0, 1}, {0, 1}, {0, 1}}, nil
+	case XYZ:
+		return [3][2]float64{{0, whiteX}, {0, whiteY}, {0, whiteZ}}, nil
+	case Lab:
+		return [3][2]float64{{0, 100}, {-128, 127}, {-128, 127}}, nil
+	case LCh:
+		return [3][2]float64{{0, 100}, {0, 150}, {0, 360}}, nil
+	case Oklab:
+		return [3][2]float64{{0, 1}, {-0.4, 0.4}, {-0.4, 0.4}}, nil
+	default:
+		return [3][2]float64{}, fmt.Errorf("unknown color space %q", space)
+	}
+}
+
+// ConvertImage zamienia obraz na macierz współrzędnych w przestrzeni space ([y][x][kanał])
+func ConvertImage(img image.Image, space Space) ([][][3]float64, error) {
+	bounds := img.Bounds()
+	out := make([][][3]float64, bounds.Dy())
+	for y := range out {
+		out[y] = make([][3]float64, bounds.Dx())
+		for x := range out[y] {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			v, err := FromRGB(c.R, c.G, c.B, space)
+			if err != nil {
+				return nil, err
+			}
+			out[y][x] = v
+		}
+	}
+	return out, nil
+}
+
+// ConvertToRGBImage odtwarza nieprzezroczysty obraz sRGB z macierzy współrzędnych w przestrzeni space
+func ConvertToRGBImage(data [][][3]float64, space Space) (*image.RGBA, error) {
+	if len(data) == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0)), nil
+	}
+	out := image.NewRGBA(image.Rect(0, 0, len(data[0]), len(data)))
+	for y, row := range data {
+		for x, v := range row {
+			r, g, b, err := ToRGB(v, space)
+			if err != nil {
+				return nil, err
+			}
+			out.SetRGBA(x, y, color.RGBA{R: r, G: g, B: b, A: 255})
+		}
+	}
+	return out, nil
+}
+
+// SplitChannels zamienia obraz na przestrzeń space i zwraca każdy kanał jako obraz w skali szarości,
+// przeskalowany z zakresu podanego przez Ranges na 0..255
+func SplitChannels(img image.Image, space Space) ([3]*image.Gray, error) {
+	var out [3]*image.Gray
+	ranges, err := Ranges(space)
+	if err != nil {
+		return out, err
+	}
+	data, err := ConvertImage(img, space)
+	if err != nil {
+		return out, err
+	}
+	bounds := img.Bounds()
+	for c := range out {
+		out[c] = image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		lo, hi := ranges[c][0], ranges[c][1]
+		for y, row := range data {
+			for x, v := range row {
+				out[c].SetGray(x, y, color.Gray{Y: clamp.UnitToByte((v[c] - lo) / (hi - lo))})
+			}
+		}
+	}
+	return out, nil
+}
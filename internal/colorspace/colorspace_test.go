@@ -0,0 +1,116 @@
+package colorspace
+
+import (
+	"math"
+	"testing"
+)
+
+// Krok siatki wartości sRGB sprawdzanych w testach przejścia w obie strony
+const gridStep = 15
+
+func TestRoundTrip(t *testing.T) {
+	for _, space := range Spaces {
+		t.Run(string(space), func(t *testing.T) {
+			for r := 0; r <= 255; r += gridStep {
+				for g := 0; g <= 255; g += gridStep {
+					for b := 0; b <= 255; b += gridStep {
+						c, err := FromRGB(uint8(r), uint8(g), uint8(b), space)
+						if err != nil {
+							t.Fatalf("FromRGB: %v", err)
+						}
+						r2, g2, b2, err := ToRGB(c, space)
+						if err != nil {
+							t.Fatalf("ToRGB: %v", err)
+						}
+						if int(r2) != r || int(g2) != g || int(b2) != b {
+							t.Fatalf("(%d, %d, %d) -> %v -> (%d, %d, %d)", r, g, b, c, r2, g2, b2)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReferenceValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func(r, g, b uint8) (float64, float64, float64)
+		r, g, b uint8
+		want    [3]float64
+		tol     float64
+	}{
+		{"red HSV", RGBToHSV, 255, 0, 0, [3]float64{0, 1, 1}, 1e-9},
+		{"dark green HSV", RGBToHSV, 0, 128, 0, [3]float64{120, 1, 0.50196}, 1e-4},
+		{"gray HSV", RGBToHSV, 128, 128, 128, [3]float64{0, 0, 0.50196}, 1e-4},
+		{"near-black HSV", RGBToHSV, 1, 0, 0, [3]float64{0, 1, 0.00392}, 1e-4},
+		// ITU-R BT.601 / JFIF (pełny zakres): Cr czerwieni wychodzi poza 255
+		{"white YCbCr601", ycbcr(YCbCr601), 255, 255, 255, [3]float64{255, 128, 128}, 1e-9},
+		{"red YCbCr601", ycbcr(YCbCr601), 255, 0, 0, [3]float64{76.245, 84.972, 255.5}, 1e-3},
+		// ITU-R BT.709: Kr = 0.2126, Kb = 0.0722
+		{"red YCbCr709", ycbcr(YCbCr709), 255, 0, 0, [3]float64{54.213, 98.784, 255.5}, 1e-3},
+		{"white XYZ", RGBToXYZ, 255, 255, 255, [3]float64{0.95047, 1, 1.08883}, 1e-3},
+		{"red XYZ", RGBToXYZ, 255, 0, 0, [3]float64{0.4124, 0.2126, 0.0193}, 1e-3},
+		{"red Lab", RGBToLab, 255, 0, 0, [3]float64{53.24, 80.09, 67.20}, 0.05},
+		{"green Lab", RGBToLab, 0, 255, 0, [3]float64{87.73, -86.18, 83.18}, 0.05},
+		{"blue Lab", RGBToLab, 0, 0, 255, [3]float64{32.30, 79.19, -107.86}, 0.05},
+		{"black Lab", RGBToLab, 0, 0, 0, [3]float64{0, 0, 0}, 1e-9},
+		{"red LCh", RGBToLCh, 255, 0, 0, [3]float64{53.24, 104.55, 40.00}, 0.05},
+		{"blue LCh", RGBToLCh, 0, 0, 255, [3]float64{32.30, 133.81, 306.29}, 0.05},
+		{"gray LCh", RGBToLCh, 128, 128, 128, [3]float64{53.59, 0, 0}, 1e-2},
+		{"near-black gray LCh", RGBToLCh, 1, 1, 1, [3]float64{0.274, 0, 0}, 1e-3},
+		{"white Oklab", RGBToOklab, 255, 255, 255, [3]float64{1, 0, 0}, 1e-3},
+		{"red Oklab", RGBToOklab, 255, 0, 0, [3]float64{0.628, 0.225, 0.126}, 1e-3},
+		{"green Oklab", RGBToOklab, 0, 255, 0, [3]float64{0.866, -0.234, 0.179}, 1e-3},
+		{"blue Oklab", RGBToOklab, 0, 0, 255, [3]float64{0.452, -0.032, -0.312}, 1e-3},
+	}
+	for _, tt := range tests {
+		x, y, z := tt.convert(tt.r, tt.g, tt.b)
+		got := [3]float64{x, y, z}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > tt.tol {
+				t.Errorf("%s: got %v, want %v (±%g)", tt.name, got, tt.want, tt.tol)
+				break
+			}
+		}
+	}
+}
+
+func TestToRGBClipping(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func() (uint8, uint8, uint8)
+		want    [3]uint8
+	}{
+		{"Lab beyond gamut", func() (uint8, uint8, uint8) { return LabToRGB(100, 200, 0) }, [3]uint8{255, 0, 255}},
+		{"Lab strong blue", func() (uint8, uint8, uint8) { return LabToRGB(50, 0, -200) }, [3]uint8{0, 160, 255}},
+		{"LCh beyond gamut", func() (uint8, uint8, uint8) { return LChToRGB(60, 150, 140) }, [3]uint8{0, 180, 0}},
+		{"Oklab above white", func() (uint8, uint8, uint8) { return OklabToRGB(1.2, 0, 0) }, [3]uint8{255, 255, 255}},
+		{"YCbCr601 corner", func() (uint8, uint8, uint8) { return YCbCrToRGB(255, 255, 255, YCbCr601) }, [3]uint8{255, 121, 255}},
+		{"YCbCr709 corner", func() (uint8, uint8, uint8) { return YCbCrToRGB(0, 0, 0, YCbCr709) }, [3]uint8{0, 84, 0}},
+		{"HSV hue above 360", func() (uint8, uint8, uint8) { return HSVToRGB(480, 1, 1) }, [3]uint8{0, 255, 0}},
+		{"HSV negative hue", func() (uint8, uint8, uint8) { return HSVToRGB(-120, 1, 1) }, [3]uint8{0, 0, 255}},
+	}
+	for _, tt := range tests {
+		r, g, b := tt.convert()
+		if got := [3]uint8{r, g, b}; got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownSpace(t *testing.T) {
+	if _, err := FromRGB(0, 0, 0, "cmyk"); err == nil {
+		t.Error("FromRGB: expected error for unknown space")
+	}
+	if _, _, _, err := ToRGB([3]float64{}, "cmyk"); err == nil {
+		t.Error("ToRGB: expected error for unknown space")
+	}
+}
+
+// ycbcr dopasowuje RGBToYCbCr do sygnatury tabeli wartości odniesienia
+func ycbcr(space Space) func(r, g, b uint8) (float64, float64, float64) {
+	return func(r, g, b uint8) (float64, float64, float64) {
+		return RGBToYCbCr(r, g, b, space)
+	}
+}
@@ -1,8 +1,11 @@
+// Package convolution wykonuje konwolucję na macierzach szarości.
+// Alfa: macierz szarości nie przechowuje alfy; ConvertToGrayMatrix ją pomija.
 package convolution
 
 import (
 	"image"
 	"image/color"
+	"image-processing/v1/internal/srgb"
 	"math"
 )
 
@@ -115,4 +118,37 @@ func Convolve(img [][]float64, kernel [][]float64, padding PaddingType) [][]floa
         }
     }
     return out
-}
\ No newline at end of file
+}
+
+// LinearizeMatrix zamienia macierz szarości w sRGB (0..255) na światło liniowe (0..255).
+// Rozmycie liczone w świetle liniowym nie przyciemnia drobnych jasnych detali.
+// Wynik pozostaje liczbą zmiennoprzecinkową; wartości całkowite są dekodowane z tablicy.
+func LinearizeMatrix(data [][]float64) [][]float64 {
+    out := make([][]float64, len(data))
+    for y := range data {
+        out[y] = make([]float64, len(data[y]))
+        for x := range data[y] {
+            v := math.Max(0, math.Min(255, data[y][x]))
+            if v == math.Trunc(v) {
+                out[y][x] = srgb.ToLinear(uint8(v)) * 255
+            } else {
+                out[y][x] = srgb.Decode(v/255) * 255
+            }
+        }
+    }
+    return out
+}
+
+// DelinearizeMatrix zamienia macierz w świetle liniowym (0..255) z powrotem na sRGB (0..255)
+// bez zaokrąglania - do 8 bitów wynik zamienia dopiero ConvertGrayMatrixToImage
+func DelinearizeMatrix(data [][]float64) [][]float64 {
+    out := make([][]float64, len(data))
+    for y := range data {
+        out[y] = make([]float64, len(data[y]))
+        for x := range data[y] {
+            l := math.Max(0, math.Min(1, data[y][x]/255))
+            out[y][x] = srgb.Encode(l) * 255
+        }
+    }
+    return out
+}
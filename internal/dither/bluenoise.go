@@ -0,0 +1,155 @@
+package dither
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Rozmiar mapy blue noise i szerokość filtru Gaussa w algorytmie void-and-cluster
+const (
+	blueNoiseSize  = 64
+	blueNoiseSigma = 1.5
+)
+
+var (
+	blueNoiseOnce sync.Once
+	blueNoiseMap  [][]float64
+)
+
+// blueNoise zwraca mapę progów wygenerowaną algorytmem void-and-cluster (Ulichney, 1993).
+// Mapa jest liczona raz, przy pierwszym użyciu, z ustalonym ziarnem - wynik jest powtarzalny.
+func blueNoise() [][]float64 {
+	blueNoiseOnce.Do(func() {
+		blueNoiseMap = voidAndCluster(blueNoiseSize, blueNoiseSigma, 1)
+	})
+	return blueNoiseMap
+}
+
+// energyField - suma wpływu Gaussa od wszystkich zapalonych pikseli, na torusie n x n
+type energyField struct {
+	n      int
+	kernel []float64 // wpływ piksela w odległości (dx, dy): kernel[dy*n+dx]
+	energy []float64
+	on     []bool
+}
+
+func newEnergyField(n int, sigma float64) *energyField {
+	f := &energyField{n: n, kernel: make([]float64, n*n), energy: make([]float64, n*n), on: make([]bool, n*n)}
+	for dy := 0; dy < n; dy++ {
+		for dx := 0; dx < n; dx++ {
+			ddx := float64(min(dx, n-dx))
+			ddy := float64(min(dy, n-dy))
+			f.kernel[dy*n+dx] = math.Exp(-(ddx*ddx + ddy*ddy) / (2 * sigma * sigma))
+		}
+	}
+	return f
+}
+
+// set zapala lub gasi piksel i aktualizuje energię
+func (f *energyField) set(p int, on bool) {
+	if f.on[p] == on {
+		return
+	}
+	f.on[p] = on
+	sign := 1.0
+	if !on {
+		sign = -1
+	}
+	px, py := p%f.n, p/f.n
+	for y := 0; y < f.n; y++ {
+		dy := (y - py + f.n) % f.n
+		for x := 0; x < f.n; x++ {
+			dx := (x - px + f.n) % f.n
+			f.energy[y*f.n+x] += sign * f.kernel[dy*f.n+dx]
+		}
+	}
+}
+
+// tightestCluster - zapalony piksel o największej energii
+func (f *energyField) tightestCluster() int {
+	best := -1
+	for p, on := range f.on {
+		if on && (best < 0 || f.energy[p] > f.energy[best]) {
+			best = p
+		}
+	}
+	return best
+}
+
+// largestVoid - zgaszony piksel o najmniejszej energii
+func (f *energyField) largestVoid() int {
+	best := -1
+	for p, on := range f.on {
+		if !on && (best < 0 || f.energy[p] < f.energy[best]) {
+			best = p
+		}
+	}
+	return best
+}
+
+func voidAndCluster(n int, sigma float64, seed int64) [][]float64 {
+	total := n * n
+	rng := rand.New(rand.NewSource(seed))
+
+	// Wzór początkowy: ok. 10% losowych pikseli, rozprowadzanych aż do równowagi
+	initial := newEnergyField(n, sigma)
+	for count := 0; count < total/10; {
+		p := rng.Intn(total)
+		if !initial.on[p] {
+			initial.set(p, true)
+			count++
+		}
+	}
+	for {
+		cluster := initial.tightestCluster()
+		initial.set(cluster, false)
+		void := initial.largestVoid()
+		initial.set(void, true)
+		if void == cluster {
+			break
+		}
+	}
+
+	ones := 0
+	for _, on := range initial.on {
+		if on {
+			ones++
+		}
+	}
+	rank := make([]int, total)
+
+	// Faza 1: usuwanie najciaśniejszych skupisk nadaje rangi pikselom wzoru początkowego
+	field := newEnergyField(n, sigma)
+	for p, on := range initial.on {
+		if on {
+			field.set(p, true)
+		}
+	}
+	for r := ones - 1; r >= 0; r-- {
+		p := field.tightestCluster()
+		field.set(p, false)
+		rank[p] = r
+	}
+
+	// Faza 2: wypełnianie największych pustek aż do zapalenia wszystkich pikseli
+	for p, on := range initial.on {
+		if on {
+			field.set(p, true)
+		}
+	}
+	for r := ones; r < total; r++ {
+		p := field.largestVoid()
+		field.set(p, true)
+		rank[p] = r
+	}
+
+	out := make([][]float64, n)
+	for y := range out {
+		out[y] = make([]float64, n)
+		for x := range out[y] {
+			out[y][x] = (float64(rank[y*n+x]) + 0.5) / float64(total)
+		}
+	}
+	return out
+}
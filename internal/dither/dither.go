@@ -0,0 +1,202 @@
+// Package dither rozprasza błąd kwantyzacji: dyfuzja błędu (Floyd-Steinberg, Jarvis-Judice-Ninke,
+// Stucki, Atkinson, Sierra) z przebiegiem serpentynowym oraz dithering uporządkowany
+// (macierze Bayera i mapa progów typu blue noise). Działa na pojedynczym kanale (wartości 0..255);
+// z pakietów reduce i binarize korzystają funkcje operujące na obrazach.
+// Alfa: pakiet nie operuje na obrazach; kanał alfa obsługują wywołujące pakiety.
+package dither
+
+import (
+	"fmt"
+	"math"
+)
+
+type Method string
+
+const (
+	None              Method = "none"
+	FloydSteinberg    Method = "floyd-steinberg"
+	JarvisJudiceNinke Method = "jjn"
+	Stucki            Method = "stucki"
+	Atkinson          Method = "atkinson"
+	Sierra            Method = "sierra"
+	SierraTwoRow      Method = "sierra2"
+	SierraLite        Method = "sierra-lite"
+	Bayer2            Method = "bayer2"
+	Bayer4            Method = "bayer4"
+	Bayer8            Method = "bayer8"
+	BlueNoise         Method = "bluenoise"
+)
+
+// Methods - wszystkie metody w kolejności prezentacji
+var Methods = []Method{None, FloydSteinberg, JarvisJudiceNinke, Stucki, Atkinson, Sierra, SierraTwoRow, SierraLite, Bayer2, Bayer4, Bayer8, BlueNoise}
+
+// Weight - udział błędu przekazywany pikselowi przesuniętemu o (DX, DY) względem bieżącego
+type Weight struct {
+	DX, DY int
+	W      float64
+}
+
+// Kernel - macierz dyfuzji błędu; suma wag / Divisor to część błędu, która jest rozpraszana
+// (Atkinson rozprasza tylko 3/4 błędu)
+type Kernel struct {
+	Weights []Weight
+	Divisor float64
+}
+
+// Kernels - macierze dyfuzji błędu dla przebiegu od lewej do prawej
+var Kernels = map[Method]Kernel{
+	FloydSteinberg: {Divisor: 16, Weights: []Weight{
+		{1, 0, 7},
+		{-1, 1, 3}, {0, 1, 5}, {1, 1, 1},
+	}},
+	JarvisJudiceNinke: {Divisor: 48, Weights: []Weight{
+		{1, 0, 7}, {2, 0, 5},
+		{-2, 1, 3}, {-1, 1, 5}, {0, 1, 7}, {1, 1, 5}, {2, 1, 3},
+		{-2, 2, 1}, {-1, 2, 3}, {0, 2, 5}, {1, 2, 3}, {2, 2, 1},
+	}},
+	Stucki: {Divisor: 42, Weights: []Weight{
+		{1, 0, 8}, {2, 0, 4},
+		{-2, 1, 2}, {-1, 1, 4}, {0, 1, 8}, {1, 1, 4}, {2, 1, 2},
+		{-2, 2, 1}, {-1, 2, 2}, {0, 2, 4}, {1, 2, 2}, {2, 2, 1},
+	}},
+	Atkinson: {Divisor: 8, Weights: []Weight{
+		{1, 0, 1}, {2, 0, 1},
+		{-1, 1, 1}, {0, 1, 1}, {1, 1, 1},
+		{0, 2, 1},
+	}},
+	Sierra: {Divisor: 32, Weights: []Weight{
+		{1, 0, 5}, {2, 0, 3},
+		{-2, 1, 2}, {-1, 1, 4}, {0, 1, 5}, {1, 1, 4}, {2, 1, 2},
+		{-1, 2, 2}, {0, 2, 3}, {1, 2, 2},
+	}},
+	SierraTwoRow: {Divisor: 16, Weights: []Weight{
+		{1, 0, 4}, {2, 0, 3},
+		{-2, 1, 1}, {-1, 1, 2}, {0, 1, 3}, {1, 1, 2}, {2, 1, 1},
+	}},
+	SierraLite: {Divisor: 4, Weights: []Weight{
+		{1, 0, 2},
+		{-1, 1, 1}, {0, 1, 1},
+	}},
+}
+
+// Options - ustawienia ditheringu
+type Options struct {
+	Method     Method
+	Serpentine bool // co drugi wiersz przetwarzany od prawej do lewej (mniej "robaczków")
+}
+
+// Apply kwantyzuje kanał plane (wartości 0..255, [y][x]) funkcją quantize, rozpraszając błąd wybraną metodą.
+// step to odstęp między sąsiednimi poziomami kwantyzacji - określa amplitudę progów ditheringu
+// uporządkowanego. Zwraca nową macierz; plane nie jest zmieniana.
+func Apply(plane [][]float64, quantize func(v float64) float64, step float64, opts Options) ([][]float64, error) {
+	switch opts.Method {
+	case None, "":
+		return mapPlane(plane, func(_, _ int, v float64) float64 { return quantize(v) }), nil
+	case Bayer2, Bayer4, Bayer8, BlueNoise:
+		thresholds := ThresholdMap(opts.Method)
+		n := len(thresholds)
+		return mapPlane(plane, func(x, y int, v float64) float64 {
+			return quantize(v + (thresholds[y%n][x%n]-0.5)*step)
+		}), nil
+	}
+	kernel, ok := Kernels[opts.Method]
+	if !ok {
+		return nil, fmt.Errorf("unknown dithering method %q", opts.Method)
+	}
+	return diffuse(plane, quantize, kernel, opts.Serpentine), nil
+}
+
+// diffuse - dyfuzja błędu; w przebiegu od prawej do lewej macierz jest odbijana poziomo
+func diffuse(plane [][]float64, quantize func(v float64) float64, kernel Kernel, serpentine bool) [][]float64 {
+	work := mapPlane(plane, func(_, _ int, v float64) float64 { return v })
+	out := make([][]float64, len(work))
+	for y := range work {
+		out[y] = make([]float64, len(work[y]))
+		w := len(work[y])
+		dir := 1
+		if serpentine && y%2 == 1 {
+			dir = -1
+		}
+		for i := 0; i < w; i++ {
+			x := i
+			if dir < 0 {
+				x = w - 1 - i
+			}
+			// Przycięcie do 0..255 nie pozwala narastać błędowi, gdy poziomy nie pokrywają całego zakresu
+			old := math.Max(0, math.Min(255, work[y][x]))
+			q := quantize(old)
+			out[y][x] = q
+			err := (old - q) / kernel.Divisor
+			for _, k := range kernel.Weights {
+				nx, ny := x+k.DX*dir, y+k.DY
+				if ny < len(work) && nx >= 0 && nx < w {
+					work[ny][nx] += err * k.W
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ThresholdMap zwraca kwadratową mapę progów z zakresu (0, 1) dla ditheringu uporządkowanego
+func ThresholdMap(method Method) [][]float64 {
+	switch method {
+	case Bayer2:
+		return bayer(2)
+	case Bayer4:
+		return bayer(4)
+	case Bayer8:
+		return bayer(8)
+	case BlueNoise:
+		return blueNoise()
+	}
+	return [][]float64{{0.5}}
+}
+
+// bayer buduje macierz Bayera n x n (n potęgą 2) rekurencyjnie: M(2n) = [4M, 4M+2; 4M+3, 4M+1]
+func bayer(n int) [][]float64 {
+	m := [][]int{{0}}
+	for size := 1; size < n; size *= 2 {
+		next := make([][]int, size*2)
+		for y := range next {
+			next[y] = make([]int, size*2)
+			for x := range next[y] {
+				v := 4 * m[y%size][x%size]
+				next[y][x] = v + [2][2]int{{0, 2}, {3, 1}}[y/size][x/size]
+			}
+		}
+		m = next
+	}
+	out := make([][]float64, n)
+	for y := range out {
+		out[y] = make([]float64, n)
+		for x := range out[y] {
+			out[y][x] = (float64(m[y][x]) + 0.5) / float64(n*n)
+		}
+	}
+	return out
+}
+
+func mapPlane(plane [][]float64, f func(x, y int, v float64) float64) [][]float64 {
+	out := make([][]float64, len(plane))
+	for y, row := range plane {
+		out[y] = make([]float64, len(row))
+		for x, v := range row {
+			out[y][x] = f(x, y, v)
+		}
+	}
+	return out
+}
+
+// NearestLevel zwraca funkcję kwantyzującą do najbliższej z podanych (rosnących) wartości
+func NearestLevel(levels []float64) func(v float64) float64 {
+	return func(v float64) float64 {
+		best := levels[0]
+		for _, l := range levels[1:] {
+			if math.Abs(l-v) < math.Abs(best-v) {
+				best = l
+			}
+		}
+		return best
+	}
+}
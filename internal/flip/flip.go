@@ -1,3 +1,5 @@
+// Package flip odbija obraz w pionie lub poziomie.
+// Alfa: piksele są kopiowane bez zmian, razem z kanałem alfa.
 package flip
 
 import "image"
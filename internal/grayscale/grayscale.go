@@ -1,3 +1,5 @@
+// Package grayscale zamienia obraz na odcienie szarości.
+// Alfa: jasność liczona jest z koloru bez premultiplikacji, kanał alfa zostaje zachowany.
 package grayscale
 
 import (
@@ -17,9 +19,9 @@ func ApplyGrayscaleToImage(img image.Image) *image.RGBA {
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			r, g, b, _ := img.At(x, y).RGBA()
-			gray := ConvertToGrayscale(uint8(r>>8), uint8(g>>8), uint8(b>>8))
-			grayColor := color.RGBA{R: gray, G: gray, B: gray, A: 255}
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			gray := ConvertToGrayscale(c.R, c.G, c.B)
+			grayColor := color.NRGBA{R: gray, G: gray, B: gray, A: c.A}
 			grayImg.Set(x, y, grayColor)
 		}
 	}
@@ -0,0 +1,174 @@
+package histogram
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "image"
+    "image-processing/v1/internal/hsl"
+    "image/color"
+    "io"
+    "math"
+    "strconv"
+)
+
+type Channel string
+
+const (
+    ChannelLuminance Channel = "luminance"
+    ChannelR         Channel = "r"
+    ChannelG         Channel = "g"
+    ChannelB         Channel = "b"
+    ChannelH         Channel = "h"
+    ChannelS         Channel = "s"
+    ChannelL         Channel = "l"
+    ChannelA         Channel = "a"
+)
+
+// AllChannels - wszystkie kanały w kolejności używanej przy eksporcie
+var AllChannels = []Channel{ChannelLuminance, ChannelR, ChannelG, ChannelB, ChannelH, ChannelS, ChannelL, ChannelA}
+
+// Histogram - zliczenia jednego kanału. Kanał H ma zakres 0..360 stopni, S i L 0..255.
+// Kanały 8-bitowe (jasność, R, G, B, A) mają zakres [0, 256): słupek i obejmuje wartości
+// całkowite v, dla których v*Bins/256 == i.
+type Histogram struct {
+    Channel    Channel   `json:"channel"`
+    Bins       int       `json:"bins"`
+    Min        float64   `json:"min"`
+    Max        float64   `json:"max"`
+    Total      int       `json:"total"`
+    Counts     []int     `json:"counts"`
+    Normalized []float64 `json:"normalized"` // liczność / liczba pikseli
+}
+
+// BinRange zwraca zakres wartości [start, end) słupka i
+func (h Histogram) BinRange(i int) (float64, float64) {
+    width := (h.Max - h.Min) / float64(h.Bins)
+    return h.Min + float64(i)*width, h.Min + float64(i+1)*width
+}
+
+// Compute liczy histogram kanału z podziałem na bins słupków (1..256 dla kanałów 8-bitowych)
+func Compute(img image.Image, channel Channel, bins int) (Histogram, error) {
+    hists, err := ComputeAll(img, []Channel{channel}, bins)
+    if err != nil {
+        return Histogram{}, err
+    }
+    return hists[0], nil
+}
+
+// ComputeAll liczy histogramy kilku kanałów w jednym przejściu po obrazie
+func ComputeAll(img image.Image, channels []Channel, bins int) ([]Histogram, error) {
+    if bins < 1 {
+        return nil, fmt.Errorf("bin count must be positive, got %d", bins)
+    }
+    hists := make([]Histogram, len(channels))
+    needHSL := false
+    for i, ch := range channels {
+        var max float64
+        switch ch {
+        case ChannelH:
+            max = 360
+            needHSL = true
+        case ChannelS, ChannelL:
+            max = 255
+            needHSL = true
+        case ChannelLuminance, ChannelR, ChannelG, ChannelB, ChannelA:
+            if bins > 256 {
+                return nil, fmt.Errorf("channel %q has 256 values, got %d bins", ch, bins)
+            }
+            max = 256
+        default:
+            return nil, fmt.Errorf("unknown histogram channel %q", ch)
+        }
+        hists[i] = Histogram{Channel: ch, Bins: bins, Min: 0, Max: max, Counts: make([]int, bins)}
+    }
+
+    bounds := img.Bounds()
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            var h, s, l float64
+            if needHSL {
+                h, s, l = hsl.RGBToHSL(c.R, c.G, c.B)
+            }
+            for i := range hists {
+                var idx int
+                switch hists[i].Channel {
+                case ChannelLuminance:
+                    idx = byteBin(brightness(c.R, c.G, c.B), bins)
+                case ChannelR:
+                    idx = byteBin(c.R, bins)
+                case ChannelG:
+                    idx = byteBin(c.G, bins)
+                case ChannelB:
+                    idx = byteBin(c.B, bins)
+                case ChannelA:
+                    idx = byteBin(c.A, bins)
+                case ChannelH:
+                    idx = floatBin(h/360, bins)
+                case ChannelS:
+                    idx = floatBin(s, bins)
+                case ChannelL:
+                    idx = floatBin(l, bins)
+                }
+                hists[i].Counts[idx]++
+            }
+        }
+    }
+
+    total := bounds.Dx() * bounds.Dy()
+    for i := range hists {
+        hists[i].Total = total
+        hists[i].Normalized = make([]float64, bins)
+        for j, c := range hists[i].Counts {
+            if total > 0 {
+                hists[i].Normalized[j] = float64(c) / float64(total)
+            }
+        }
+    }
+    return hists, nil
+}
+
+// byteBin - słupek dla wartości 8-bitowej
+func byteBin(v uint8, bins int) int {
+    return int(v) * bins / 256
+}
+
+// floatBin - słupek dla wartości z zakresu 0..1
+func floatBin(v float64, bins int) int {
+    idx := int(math.Floor(v * float64(bins)))
+    return max(0, min(bins-1, idx))
+}
+
+// WriteCSV zapisuje histogramy jako CSV: channel,bin,start,end,count,normalized
+func WriteCSV(w io.Writer, hists []Histogram) error {
+    cw := csv.NewWriter(w)
+    if err := cw.Write([]string{"channel", "bin", "start", "end", "count", "normalized"}); err != nil {
+        return err
+    }
+    for _, h := range hists {
+        for i, c := range h.Counts {
+            start, end := h.BinRange(i)
+            err := cw.Write([]string{
+                string(h.Channel),
+                strconv.Itoa(i),
+                strconv.FormatFloat(start, 'f', -1, 64),
+                strconv.FormatFloat(end, 'f', -1, 64),
+                strconv.Itoa(c),
+                strconv.FormatFloat(h.Normalized[i], 'g', -1, 64),
+            })
+            if err != nil {
+                return err
+            }
+        }
+    }
+    cw.Flush()
+    return cw.Error()
+}
+
+// WriteJSON zapisuje histogramy jako tablicę JSON
+func WriteJSON(w io.Writer, hists []Histogram) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(hists)
+}
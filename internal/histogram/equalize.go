@@ -0,0 +1,232 @@
+package histogram
+
+import (
+    "image"
+    "image-processing/v1/internal/hsl"
+    "image/color"
+    "math"
+)
+
+// EqualizeGray wyrównuje histogram jasności i zwraca obraz w skali szarości
+func EqualizeGray(img image.Image) *image.Gray {
+    lum := luminanceChannel(img)
+    return channelToGray(applyLUT(lum, equalizationLUT(countValues(lum))))
+}
+
+// EqualizeLightness wyrównuje histogram składowej L (HSL) obrazu barwnego; odcień i nasycenie zostają bez zmian
+func EqualizeLightness(img image.Image) *image.RGBA {
+    light := lightnessChannel(img)
+    return replaceLightness(img, applyLUT(light, equalizationLUT(countValues(light))))
+}
+
+// CLAHE - adaptacyjne wyrównanie histogramu z ograniczeniem kontrastu, liczone na jasności.
+// tilesX, tilesY - liczba kafelków siatki; clipLimit - limit słupka jako wielokrotność
+// średniej liczby pikseli na słupek (typowo 2..4; wartość <= 0 wyłącza ograniczanie).
+func CLAHE(img image.Image, tilesX, tilesY int, clipLimit float64) *image.Gray {
+    return channelToGray(clahe(luminanceChannel(img), tilesX, tilesY, clipLimit))
+}
+
+// CLAHELightness - CLAHE na składowej L (HSL) obrazu barwnego
+func CLAHELightness(img image.Image, tilesX, tilesY int, clipLimit float64) *image.RGBA {
+    return replaceLightness(img, clahe(lightnessChannel(img), tilesX, tilesY, clipLimit))
+}
+
+// equalizationLUT buduje tablicę przejścia z dystrybuanty histogramu
+func equalizationLUT(hist [256]int) [256]uint8 {
+    var lut [256]uint8
+    total, cdfMin := 0, 0
+    for _, c := range hist {
+        total += c
+    }
+    for _, c := range hist {
+        if c > 0 {
+            cdfMin = c
+            break
+        }
+    }
+    if total == cdfMin {
+        // Obraz jednolity - brak czego wyrównywać
+        for i := range lut {
+            lut[i] = uint8(i)
+        }
+        return lut
+    }
+    cdf := 0
+    for i, c := range hist {
+        cdf += c
+        v := math.Round(float64(cdf-cdfMin) / float64(total-cdfMin) * 255)
+        lut[i] = uint8(math.Max(0, v))
+    }
+    return lut
+}
+
+// clahe wyrównuje kanał lokalnie, interpolując dwuliniowo tablice sąsiednich kafelków
+func clahe(ch [][]uint8, tilesX, tilesY int, clipLimit float64) [][]uint8 {
+    if len(ch) == 0 || len(ch[0]) == 0 {
+        return ch // pusty obraz - nie ma czego wyrównywać
+    }
+    h, w := len(ch), len(ch[0])
+    tilesX = max(1, min(tilesX, w))
+    tilesY = max(1, min(tilesY, h))
+
+    luts := make([][][256]uint8, tilesY)
+    for ty := 0; ty < tilesY; ty++ {
+        luts[ty] = make([][256]uint8, tilesX)
+        y0, y1 := h*ty/tilesY, h*(ty+1)/tilesY
+        for tx := 0; tx < tilesX; tx++ {
+            x0, x1 := w*tx/tilesX, w*(tx+1)/tilesX
+            var hist [256]int
+            for y := y0; y < y1; y++ {
+                for x := x0; x < x1; x++ {
+                    hist[ch[y][x]]++
+                }
+            }
+            if clipLimit > 0 {
+                limit := max(1, int(clipLimit*float64((y1-y0)*(x1-x0))/256))
+                hist = clipHistogram(hist, limit)
+            }
+            luts[ty][tx] = cumulativeLUT(hist)
+        }
+    }
+
+    tileW := float64(w) / float64(tilesX)
+    tileH := float64(h) / float64(tilesY)
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        // Położenie względem środków kafelków
+        fy := (float64(y)+0.5)/tileH - 0.5
+        ty0 := int(math.Floor(fy))
+        wy := fy - float64(ty0)
+        ty1 := min(ty0+1, tilesY-1)
+        ty0 = max(ty0, 0)
+        for x := 0; x < w; x++ {
+            fx := (float64(x)+0.5)/tileW - 0.5
+            tx0 := int(math.Floor(fx))
+            wx := fx - float64(tx0)
+            tx1 := min(tx0+1, tilesX-1)
+            tx0 = max(tx0, 0)
+            v := ch[y][x]
+            top := (1-wx)*float64(luts[ty0][tx0][v]) + wx*float64(luts[ty0][tx1][v])
+            bottom := (1-wx)*float64(luts[ty1][tx0][v]) + wx*float64(luts[ty1][tx1][v])
+            out[y][x] = uint8(math.Round((1-wy)*top + wy*bottom))
+        }
+    }
+    return out
+}
+
+// clipHistogram obcina słupki powyżej limitu i rozdziela nadmiar równo między wszystkie słupki
+func clipHistogram(hist [256]int, limit int) [256]int {
+    excess := 0
+    for i, c := range hist {
+        if c > limit {
+            excess += c - limit
+            hist[i] = limit
+        }
+    }
+    for i := range hist {
+        hist[i] += excess / 256
+    }
+    for i := 0; i < excess%256; i++ {
+        hist[i*256/(excess%256)]++
+    }
+    return hist
+}
+
+// cumulativeLUT - tablica z dystrybuanty (bez przesunięcia minimum, jak w klasycznym CLAHE)
+func cumulativeLUT(hist [256]int) [256]uint8 {
+    var lut [256]uint8
+    total := 0
+    for _, c := range hist {
+        total += c
+    }
+    if total == 0 {
+        return lut
+    }
+    cdf := 0
+    for i, c := range hist {
+        cdf += c
+        lut[i] = uint8(math.Round(float64(cdf) / float64(total) * 255))
+    }
+    return lut
+}
+
+// countValues zlicza wartości kanału
+func countValues(ch [][]uint8) [256]int {
+    var hist [256]int
+    for _, row := range ch {
+        for _, v := range row {
+            hist[v]++
+        }
+    }
+    return hist
+}
+
+func applyLUT(ch [][]uint8, lut [256]uint8) [][]uint8 {
+    out := make([][]uint8, len(ch))
+    for y, row := range ch {
+        out[y] = make([]uint8, len(row))
+        for x, v := range row {
+            out[y][x] = lut[v]
+        }
+    }
+    return out
+}
+
+// luminanceChannel - jasność (ta sama co w histogramie, zob. brightness) każdego piksela
+func luminanceChannel(img image.Image) [][]uint8 {
+    bounds := img.Bounds()
+    out := make([][]uint8, bounds.Dy())
+    for y := range out {
+        out[y] = make([]uint8, bounds.Dx())
+        for x := range out[y] {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            out[y][x] = brightness(c.R, c.G, c.B)
+        }
+    }
+    return out
+}
+
+// lightnessChannel - składowa L (HSL) każdego piksela przeskalowana do 0..255
+func lightnessChannel(img image.Image) [][]uint8 {
+    bounds := img.Bounds()
+    out := make([][]uint8, bounds.Dy())
+    for y := range out {
+        out[y] = make([]uint8, bounds.Dx())
+        for x := range out[y] {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            _, _, l := hsl.RGBToHSL(c.R, c.G, c.B)
+            out[y][x] = uint8(math.Round(l * 255))
+        }
+    }
+    return out
+}
+
+// replaceLightness podmienia składową L obrazu, zachowując odcień, nasycenie i alfę
+func replaceLightness(img image.Image, light [][]uint8) *image.RGBA {
+    bounds := img.Bounds()
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            h, s, _ := hsl.RGBToHSL(c.R, c.G, c.B)
+            r, g, b := hsl.HSLToRGB(h, s, float64(light[y][x])/255)
+            out.Set(x, y, color.NRGBA{R: r, G: g, B: b, A: c.A})
+        }
+    }
+    return out
+}
+
+func channelToGray(ch [][]uint8) *image.Gray {
+    w := 0
+    if len(ch) > 0 {
+        w = len(ch[0])
+    }
+    out := image.NewGray(image.Rect(0, 0, w, len(ch)))
+    for y, row := range ch {
+        for x, v := range row {
+            out.SetGray(x, y, color.Gray{Y: v})
+        }
+    }
+    return out
+}
@@ -1,6 +1,9 @@
+// Package histogram generuje wykresy histogramów.
+// Alfa: kanał alfa jest pomijany przy zliczaniu.
 package histogram
 
 import (
+    "fmt"
     "image"
     "image/color"
     _ "image/jpeg"
@@ -19,6 +22,9 @@ import (
 // tryb - "jasnosc" lub "rgb"
 // outputDir - folder, do którego zostanie zapisany histogram
 func GenerateHistogram(path string, tryb string, outputDir string) error {
+    if tryb != "jasnosc" && tryb != "rgb" {
+        return nil
+    }
     file, err := os.Open(path)
     if err != nil {
         return err
@@ -30,105 +36,226 @@ func GenerateHistogram(path string, tryb string, outputDir string) error {
         return err
     }
 
+    filename := filepath.Join(outputDir, "histogram_"+tryb+".png")
+    return PlotHistogram(img, tryb, filename, PlotOptions{})
+}
+
+// PlotOptions - ustawienia wykresu; wartości zerowe dają wykres taki jak GenerateHistogram
+type PlotOptions struct {
+    LogScale   bool        // logarytmiczna oś Y
+    Cumulative bool        // histogram skumulowany (dystrybuanta)
+    Filled     bool        // wypełnienie pola pod krzywą zamiast samej linii
+    Compare    image.Image // obraz "przed" rysowany przerywaną linią na tym samym wykresie
+    Language   string      // "pl" (domyślnie) lub "en"
+    Width      vg.Length   // domyślnie 8 cali
+    Height     vg.Length   // domyślnie 4 cale
+}
+
+// Podpisy wykresu w obsługiwanych językach
+var plotLabels = map[string]map[string]string{
+    "pl": {
+        "jasnosc":    "Histogram jasności",
+        "rgb":        "Histogram RGB",
+        "xjasnosc":   "Jasność",
+        "xrgb":       "Wartość kanału",
+        "y":          "Znormalizowana liczba pikseli",
+        "cumulative": "skumulowany",
+        "before":     "przed",
+        "after":      "po",
+    },
+    "en": {
+        "jasnosc":    "Brightness histogram",
+        "rgb":        "RGB histogram",
+        "xjasnosc":   "Brightness",
+        "xrgb":       "Channel value",
+        "y":          "Normalized pixel count",
+        "cumulative": "cumulative",
+        "before":     "before",
+        "after":      "after",
+    },
+}
+
+// PlotHistogram rysuje histogram jasności ("jasnosc") lub RGB ("rgb") obrazu w pamięci,
+// np. wyniku pośredniego przetwarzania. Format pliku wynika z rozszerzenia (.png, .svg, .pdf, ...).
+func PlotHistogram(img image.Image, tryb string, filename string, opts PlotOptions) error {
+    labels, ok := plotLabels[opts.Language]
+    if !ok {
+        labels = plotLabels["pl"]
+    }
+    if opts.Width == 0 {
+        opts.Width = 8 * vg.Inch
+    }
+    if opts.Height == 0 {
+        opts.Height = 4 * vg.Inch
+    }
+
+    var colors []color.Color
+    var series func(image.Image) [][256]int
     switch tryb {
     case "jasnosc":
-        hist := make([]float64, 256)
-        var max float64
-        bounds := img.Bounds()
-        for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-            for x := bounds.Min.X; x < bounds.Max.X; x++ {
-                r, g, b, _ := img.At(x, y).RGBA()
-                rr := float64(r >> 8)
-                gg := float64(g >> 8)
-                bb := float64(b >> 8)
-                brightness := 0.299*rr + 0.587*gg + 0.114*bb
-                idx := int(math.Round(brightness))
-                if idx > 255 {
-                    idx = 255
-                }
-                hist[idx]++
-                if hist[idx] > max {
-                    max = hist[idx]
-                }
+        colors = []color.Color{color.Black}
+        series = func(img image.Image) [][256]int { return [][256]int{countBrightness(img)} }
+    case "rgb":
+        colors = []color.Color{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}}
+        series = func(img image.Image) [][256]int {
+            counts := countRGB(img)
+            return counts[:]
+        }
+    default:
+        return fmt.Errorf("unknown histogram mode %q", tryb)
+    }
+
+    p := plot.New()
+    p.Title.Text = labels[tryb]
+    if opts.Cumulative {
+        p.Title.Text += " (" + labels["cumulative"] + ")"
+    }
+    p.X.Label.Text = labels["x"+tryb]
+    p.Y.Label.Text = labels["y"]
+
+    after := prepareSeries(series(img), opts)
+    var before [][]float64
+    if opts.Compare != nil {
+        before = prepareSeries(series(opts.Compare), opts)
+    }
+    if opts.LogScale {
+        // Oś logarytmiczna nie przyjmuje zer - zastępujemy je połową najmniejszej dodatniej wartości
+        floor := smallestPositive(append(after, before...)) / 2
+        for _, s := range append(after, before...) {
+            for i := range s {
+                s[i] = math.Max(s[i], floor)
             }
         }
-        for i := range hist {
-            hist[i] /= max
+        p.Y.Scale = plot.LogScale{}
+        p.Y.Tick.Marker = plot.LogTicks{Prec: -1}
+    }
+
+    for i, values := range after {
+        line, err := plotter.NewLine(toXYs(values))
+        if err != nil {
+            return err
+        }
+        line.Color = colors[i]
+        if opts.Filled {
+            line.FillColor = withAlpha(colors[i], 80)
         }
-        p := plot.New()
-        p.Title.Text = "Histogram jasności"
-        p.X.Label.Text = "Jasność"
-        p.Y.Label.Text = "Znormalizowana liczba pikseli"
-        pts := make(plotter.XYs, 256)
-        for i := 0; i < 256; i++ {
-            pts[i].X = float64(i)
-            pts[i].Y = hist[i]
+        p.Add(line)
+        if opts.Compare != nil && i == 0 {
+            p.Legend.Add(labels["after"], line)
         }
-        line, err := plotter.NewLine(pts)
+    }
+    for i, values := range before {
+        line, err := plotter.NewLine(toXYs(values))
         if err != nil {
             return err
         }
-        line.Color = color.Black
+        line.Color = colors[i]
+        line.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
         p.Add(line)
-        filename := filepath.Join(outputDir, "histogram_jasnosc.png")
-        return p.Save(8*vg.Inch, 4*vg.Inch, filename)
+        if i == 0 {
+            p.Legend.Add(labels["before"], line)
+        }
+    }
+    return p.Save(opts.Width, opts.Height, filename)
+}
 
-    case "rgb":
-        histR := make([]float64, 256)
-        histG := make([]float64, 256)
-        histB := make([]float64, 256)
-        var maxR, maxG, maxB float64
-        bounds := img.Bounds()
-        for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-            for x := bounds.Min.X; x < bounds.Max.X; x++ {
-                r, g, b, _ := img.At(x, y).RGBA()
-                ri := int(r >> 8)
-                gi := int(g >> 8)
-                bi := int(b >> 8)
-                histR[ri]++
-                histG[gi]++
-                histB[bi]++
-                if histR[ri] > maxR {
-                    maxR = histR[ri]
-                }
-                if histG[gi] > maxG {
-                    maxG = histG[gi]
-                }
-                if histB[bi] > maxB {
-                    maxB = histB[bi]
-                }
+// prepareSeries normalizuje liczności: do maksimum albo, dla histogramu skumulowanego, do sumy
+func prepareSeries(counts [][256]int, opts PlotOptions) [][]float64 {
+    out := make([][]float64, len(counts))
+    for i, c := range counts {
+        if !opts.Cumulative {
+            out[i] = normalizeToMax(c)
+            continue
+        }
+        out[i] = make([]float64, 256)
+        sum := 0.0
+        for j, v := range c {
+            sum += float64(v)
+            out[i][j] = sum
+        }
+        for j := range out[i] {
+            if sum > 0 {
+                out[i][j] /= sum
+            }
+        }
+    }
+    return out
+}
+
+func toXYs(values []float64) plotter.XYs {
+    pts := make(plotter.XYs, len(values))
+    for i, v := range values {
+        pts[i].X = float64(i)
+        pts[i].Y = v
+    }
+    return pts
+}
+
+func smallestPositive(series [][]float64) float64 {
+    min := 1.0
+    for _, s := range series {
+        for _, v := range s {
+            if v > 0 && v < min {
+                min = v
             }
         }
-        for i := 0; i < 256; i++ {
-            histR[i] /= maxR
-            histG[i] /= maxG
-            histB[i] /= maxB
+    }
+    return min
+}
+
+func withAlpha(c color.Color, alpha uint8) color.Color {
+    n := color.NRGBAModel.Convert(c).(color.NRGBA)
+    n.A = alpha
+    return n
+}
+
+// countBrightness zlicza jasność pikseli (0.299R + 0.587G + 0.114B, zaokrąglona)
+func countBrightness(img image.Image) [256]int {
+    var hist [256]int
+    bounds := img.Bounds()
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            hist[brightness(c.R, c.G, c.B)]++
+        }
+    }
+    return hist
+}
+
+// countRGB zlicza wartości kanałów R, G i B
+func countRGB(img image.Image) [3][256]int {
+    var hist [3][256]int
+    bounds := img.Bounds()
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            hist[0][c.R]++
+            hist[1][c.G]++
+            hist[2][c.B]++
+        }
+    }
+    return hist
+}
+
+// brightness - zaokrąglona jasność piksela
+func brightness(r, g, b uint8) uint8 {
+    v := math.Round(0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b))
+    return uint8(math.Min(v, 255))
+}
+
+// normalizeToMax dzieli liczności przez największą z nich
+func normalizeToMax(counts [256]int) []float64 {
+    hist := make([]float64, 256)
+    max := 0
+    for _, c := range counts {
+        if c > max {
+            max = c
         }
-        p := plot.New()
-        p.Title.Text = "Histogram RGB"
-        p.X.Label.Text = "Wartość kanału"
-        p.Y.Label.Text = "Znormalizowana liczba pikseli"
-        ptsR := make(plotter.XYs, 256)
-        ptsG := make(plotter.XYs, 256)
-        ptsB := make(plotter.XYs, 256)
-        for i := 0; i < 256; i++ {
-            ptsR[i].X = float64(i)
-            ptsR[i].Y = histR[i]
-            ptsG[i].X = float64(i)
-            ptsG[i].Y = histG[i]
-            ptsB[i].X = float64(i)
-            ptsB[i].Y = histB[i]
+    }
+    for i, c := range counts {
+        if max > 0 {
+            hist[i] = float64(c) / float64(max)
         }
-        lineR, _ := plotter.NewLine(ptsR)
-        lineG, _ := plotter.NewLine(ptsG)
-        lineB, _ := plotter.NewLine(ptsB)
-        lineR.Color = color.RGBA{255, 0, 0, 255}
-        lineG.Color = color.RGBA{0, 255, 0, 255}
-        lineB.Color = color.RGBA{0, 0, 255, 255}
-        p.Add(lineR, lineG, lineB)
-        filename := filepath.Join(outputDir, "histogram_rgb.png")
-        return p.Save(8*vg.Inch, 4*vg.Inch, filename)
-    default:
-        return nil
     }
-}
\ No newline at end of file
+    return hist
+}
@@ -0,0 +1,161 @@
+package histogram
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "math"
+)
+
+type MatchMode string
+
+const (
+    PerChannel MatchMode = "channels"  // każdy z kanałów R, G, B dopasowywany osobno
+    Luminance  MatchMode = "luminance" // dopasowywana jasność, kolor przesuwany o tę samą wartość
+)
+
+// MatchHistogram dopasowuje histogram obrazu do histogramu obrazu wzorcowego ref.
+// Pozwala ujednolicić wygląd serii zdjęć. Kanał alfa zostaje zachowany.
+// Obraz wzorcowy nie może być pusty ani całkowicie przezroczysty.
+func MatchHistogram(img, ref image.Image, mode MatchMode) (*image.RGBA, error) {
+    if !hasVisiblePixels(ref) {
+        return nil, fmt.Errorf("reference image is empty or fully transparent")
+    }
+    if mode == Luminance {
+        target := countBrightness(ref)
+        return matchLuminance(img, toDistribution(target)), nil
+    }
+    targets := countRGB(ref)
+    return matchChannels(img, [3][256]float64{
+        toDistribution(targets[0]),
+        toDistribution(targets[1]),
+        toDistribution(targets[2]),
+    }), nil
+}
+
+// hasVisiblePixels sprawdza, czy obraz ma choć jeden piksel o niezerowej alfie
+func hasVisiblePixels(img image.Image) bool {
+    bounds := img.Bounds()
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            if _, _, _, a := img.At(x, y).RGBA(); a > 0 {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// MatchDistribution dopasowuje histogram obrazu do zadanego rozkładu target (wagi dla wartości 0..255,
+// nie muszą się sumować do 1). W trybie PerChannel ten sam rozkład jest używany dla R, G i B.
+// Wagi muszą być skończone i nieujemne, a ich suma dodatnia.
+func MatchDistribution(img image.Image, target [256]float64, mode MatchMode) (*image.RGBA, error) {
+    sum := 0.0
+    for i, v := range target {
+        if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+            return nil, fmt.Errorf("invalid target weight %v for value %d: must be finite and non-negative", v, i)
+        }
+        sum += v
+    }
+    if sum <= 0 || math.IsInf(sum, 0) {
+        return nil, fmt.Errorf("invalid target distribution: sum of weights must be positive and finite")
+    }
+    if mode == Luminance {
+        return matchLuminance(img, target), nil
+    }
+    return matchChannels(img, [3][256]float64{target, target, target}), nil
+}
+
+// matchingLUT dla każdej wartości źródła wybiera najmniejszą wartość docelową,
+// której dystrybuanta nie jest mniejsza od dystrybuanty źródła
+func matchingLUT(src [256]int, target [256]float64) [256]uint8 {
+    var lut [256]uint8
+    srcCDF := cdf(toDistribution(src))
+    targetCDF := cdf(target)
+    j := 0
+    for i := 0; i < 256; i++ {
+        for j < 255 && targetCDF[j] < srcCDF[i]-1e-12 {
+            j++
+        }
+        lut[i] = uint8(j)
+    }
+    return lut
+}
+
+func matchChannels(img image.Image, targets [3][256]float64) *image.RGBA {
+    counts := countRGB(img)
+    var luts [3][256]uint8
+    for c := range luts {
+        luts[c] = matchingLUT(counts[c], targets[c])
+    }
+    bounds := img.Bounds()
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            out.Set(x, y, color.NRGBA{R: luts[0][c.R], G: luts[1][c.G], B: luts[2][c.B], A: c.A})
+        }
+    }
+    return out
+}
+
+func matchLuminance(img image.Image, target [256]float64) *image.RGBA {
+    return ShiftLuminance(img, matchingLUT(countBrightness(img), target))
+}
+
+// ShiftLuminance przepuszcza jasność piksela przez tablicę lut i przesuwa R, G, B o tę samą wartość,
+// dzięki czemu odcień zostaje zachowany. Kanał alfa zostaje zachowany.
+func ShiftLuminance(img image.Image, lut [256]uint8) *image.RGBA {
+    bounds := img.Bounds()
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            v := brightness(c.R, c.G, c.B)
+            shift := int(lut[v]) - int(v)
+            out.Set(x, y, color.NRGBA{
+                R: shiftValue(c.R, shift),
+                G: shiftValue(c.G, shift),
+                B: shiftValue(c.B, shift),
+                A: c.A,
+            })
+        }
+    }
+    return out
+}
+
+// toDistribution zamienia liczności na rozkład sumujący się do 1
+func toDistribution(counts [256]int) [256]float64 {
+    var dist [256]float64
+    total := 0
+    for _, c := range counts {
+        total += c
+    }
+    if total == 0 {
+        return dist
+    }
+    for i, c := range counts {
+        dist[i] = float64(c) / float64(total)
+    }
+    return dist
+}
+
+// cdf - dystrybuanta rozkładu (normalizowana)
+func cdf(dist [256]float64) [256]float64 {
+    var out [256]float64
+    sum := 0.0
+    for i, v := range dist {
+        sum += v
+        out[i] = sum
+    }
+    if sum > 0 {
+        for i := range out {
+            out[i] /= sum
+        }
+    }
+    return out
+}
+
+func shiftValue(v uint8, shift int) uint8 {
+    return uint8(max(0, min(255, int(v)+shift)))
+}
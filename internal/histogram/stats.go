@@ -0,0 +1,209 @@
+package histogram
+
+import (
+    "encoding/json"
+    "fmt"
+    "image"
+    "image/color"
+    "io"
+    "math"
+    "sort"
+    "strings"
+)
+
+// StatsPercentiles - percentyle podawane w raporcie
+var StatsPercentiles = []float64{1, 5, 25, 75, 95, 99}
+
+// Piksel jest uznawany za szary, gdy różnica między jego kanałami nie przekracza grayTolerance
+const grayTolerance = 4
+
+// Udział pikseli barwnych, przy którym obraz nadal uznajemy za szary (szum, artefakty JPEG)
+const grayMaxColored = 0.001
+
+// ChannelStats - statystyki jednego kanału, liczone z histogramu 256 słupków
+type ChannelStats struct {
+    Channel     Channel            `json:"channel"`
+    Min         float64            `json:"min"`
+    Max         float64            `json:"max"`
+    Mean        float64            `json:"mean"`
+    Median      float64            `json:"median"`
+    StdDev      float64            `json:"stddev"`
+    Percentiles map[string]float64 `json:"percentiles"`  // klucze "p1", "p5", ...
+    Entropy     float64            `json:"entropy"`      // w bitach, 0..8 dla 256 słupków
+    ClippedLow  float64            `json:"clipped_low"`  // % pikseli w najniższym słupku
+    ClippedHigh float64            `json:"clipped_high"` // % pikseli w najwyższym słupku
+}
+
+// DominantColor - jeden z najczęstszych kolorów (średnia pikseli z jednego kubełka 4 bity/kanał)
+type DominantColor struct {
+    Color   string  `json:"color"` // #rrggbb
+    Count   int     `json:"count"`
+    Percent float64 `json:"percent"`
+}
+
+// Stats - raport statystyk obrazu
+type Stats struct {
+    Width          int             `json:"width"`
+    Height         int             `json:"height"`
+    Channels       []ChannelStats  `json:"channels"`
+    UniqueColors   int             `json:"unique_colors"` // liczba różnych kolorów RGB (bez alfy)
+    Grayscale      bool            `json:"grayscale"`
+    DominantColors []DominantColor `json:"dominant_colors"`
+}
+
+// ComputeStats liczy statystyki podanych kanałów oraz dominant najczęstszych kolorów
+func ComputeStats(img image.Image, channels []Channel, dominant int) (Stats, error) {
+    hists, err := ComputeAll(img, channels, 256)
+    if err != nil {
+        return Stats{}, err
+    }
+    bounds := img.Bounds()
+    stats := Stats{Width: bounds.Dx(), Height: bounds.Dy()}
+    for _, h := range hists {
+        stats.Channels = append(stats.Channels, channelStats(h))
+    }
+    stats.UniqueColors, stats.Grayscale, stats.DominantColors = colorStats(img, dominant)
+    return stats, nil
+}
+
+// channelStats - dla kanałów 8-bitowych wartością słupka jest jego numer, dla kanału H środek przedziału w stopniach
+func channelStats(h Histogram) ChannelStats {
+    value := func(i int) float64 {
+        if h.Channel == ChannelH {
+            start, end := h.BinRange(i)
+            return (start + end) / 2
+        }
+        return float64(i)
+    }
+
+    cs := ChannelStats{Channel: h.Channel, Percentiles: map[string]float64{}}
+    if h.Total == 0 {
+        return cs
+    }
+    first, last := -1, 0
+    sum, entropy := 0.0, 0.0
+    for i, c := range h.Counts {
+        if c == 0 {
+            continue
+        }
+        if first < 0 {
+            first = i
+        }
+        last = i
+        sum += float64(c) * value(i)
+        p := float64(c) / float64(h.Total)
+        entropy -= p * math.Log2(p)
+    }
+    cs.Min, cs.Max = value(first), value(last)
+    cs.Mean = sum / float64(h.Total)
+    variance := 0.0
+    for i, c := range h.Counts {
+        d := value(i) - cs.Mean
+        variance += float64(c) * d * d
+    }
+    cs.StdDev = math.Sqrt(variance / float64(h.Total))
+    cs.Entropy = entropy
+    cs.Median = value(percentileBin(h, 50))
+    for _, p := range StatsPercentiles {
+        cs.Percentiles[fmt.Sprintf("p%g", p)] = value(percentileBin(h, p))
+    }
+    cs.ClippedLow = 100 * float64(h.Counts[0]) / float64(h.Total)
+    cs.ClippedHigh = 100 * float64(h.Counts[h.Bins-1]) / float64(h.Total)
+    return cs
+}
+
+// percentileBin - pierwszy słupek, w którym skumulowana liczność osiąga p procent pikseli
+func percentileBin(h Histogram, p float64) int {
+    target := p / 100 * float64(h.Total)
+    sum := 0
+    for i, c := range h.Counts {
+        sum += c
+        if c > 0 && float64(sum) >= target {
+            return i
+        }
+    }
+    return h.Bins - 1
+}
+
+// colorStats liczy różne kolory, sprawdza czy obraz jest szary i wybiera kolory dominujące
+func colorStats(img image.Image, dominant int) (int, bool, []DominantColor) {
+    seen := make([]uint64, 1<<24/64)
+    type bucket struct {
+        count   int
+        r, g, b int
+    }
+    buckets := make([]bucket, 1<<12)
+    unique, colored := 0, 0
+
+    bounds := img.Bounds()
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            key := int(c.R)<<16 | int(c.G)<<8 | int(c.B)
+            if seen[key/64]&(1<<(key%64)) == 0 {
+                seen[key/64] |= 1 << (key % 64)
+                unique++
+            }
+            if max(c.R, c.G, c.B)-min(c.R, c.G, c.B) > grayTolerance {
+                colored++
+            }
+            b := &buckets[int(c.R>>4)<<8|int(c.G>>4)<<4|int(c.B>>4)]
+            b.count++
+            b.r += int(c.R)
+            b.g += int(c.G)
+            b.b += int(c.B)
+        }
+    }
+
+    total := bounds.Dx() * bounds.Dy()
+    sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].count > buckets[j].count })
+    colors := []DominantColor{}
+    for _, b := range buckets {
+        if len(colors) >= dominant || b.count == 0 {
+            break
+        }
+        colors = append(colors, DominantColor{
+            Color: fmt.Sprintf("#%02x%02x%02x",
+                (b.r+b.count/2)/b.count, (b.g+b.count/2)/b.count, (b.b+b.count/2)/b.count),
+            Count:   b.count,
+            Percent: 100 * float64(b.count) / float64(total),
+        })
+    }
+    grayscale := total > 0 && float64(colored)/float64(total) <= grayMaxColored
+    return unique, grayscale, colors
+}
+
+// WriteStatsText zapisuje raport w czytelnej postaci tekstowej
+func WriteStatsText(w io.Writer, s Stats) error {
+    var sb strings.Builder
+    fmt.Fprintf(&sb, "Size:          %dx%d\n", s.Width, s.Height)
+    fmt.Fprintf(&sb, "Unique colors: %d\n", s.UniqueColors)
+    fmt.Fprintf(&sb, "Grayscale:     %t\n", s.Grayscale)
+    for _, cs := range s.Channels {
+        fmt.Fprintf(&sb, "\n[%s]\n", cs.Channel)
+        fmt.Fprintf(&sb, "  min %.4g  max %.4g  mean %.4g  median %.4g  stddev %.4g\n",
+            cs.Min, cs.Max, cs.Mean, cs.Median, cs.StdDev)
+        sb.WriteString("  percentiles")
+        for _, p := range StatsPercentiles {
+            key := fmt.Sprintf("p%g", p)
+            fmt.Fprintf(&sb, "  %s %.4g", key, cs.Percentiles[key])
+        }
+        fmt.Fprintf(&sb, "\n  entropy %.4f bits  clipped low %.2f%%  clipped high %.2f%%\n",
+            cs.Entropy, cs.ClippedLow, cs.ClippedHigh)
+    }
+    if len(s.DominantColors) > 0 {
+        sb.WriteString("\nDominant colors:\n")
+        for _, d := range s.DominantColors {
+            fmt.Fprintf(&sb, "  %s  %6.2f%%  (%d px)\n", d.Color, d.Percent, d.Count)
+        }
+    }
+    _, err := io.WriteString(w, sb.String())
+    return err
+}
+
+// WriteStatsJSON zapisuje raport jako JSON
+func WriteStatsJSON(w io.Writer, s Stats) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(s)
+}
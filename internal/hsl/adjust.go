@@ -0,0 +1,126 @@
+package hsl
+
+import (
+    "image"
+    "image/color"
+    "math"
+)
+
+// Adjustment - zmiana barwy: Hue to obrót odcienia w stopniach, Saturation względna zmiana nasycenia
+// (nasycenie mnożone przez 1+Saturation: -1 - szarość, 0.5 - o połowę większe), Lightness przesunięcie
+// jasności (-1..1). Wartość zerowa Adjustment{} nie zmienia obrazu.
+type Adjustment struct {
+    Hue        float64
+    Saturation float64
+    Lightness  float64
+}
+
+// HueRange - zakres odcieni dla korekty selektywnej. Odcienie w odległości do Width/2 od Center
+// są zmieniane w pełni, dalej wpływ maleje płynnie do zera na szerokości Falloff (w stopniach).
+type HueRange struct {
+    Center  float64
+    Width   float64
+    Falloff float64
+}
+
+// RotateHue obraca odcień wszystkich pikseli o degrees stopni
+func RotateHue(img image.Image, degrees float64) *image.RGBA {
+    return Adjust(img, Adjustment{Hue: degrees})
+}
+
+// ScaleSaturation mnoży nasycenie przez factor (wynik przycinany do 0..1)
+func ScaleSaturation(img image.Image, factor float64) *image.RGBA {
+    return Adjust(img, Adjustment{Saturation: factor - 1})
+}
+
+// ShiftLightness przesuwa składową L o delta (-1..1)
+func ShiftLightness(img image.Image, delta float64) *image.RGBA {
+    return Adjust(img, Adjustment{Lightness: delta})
+}
+
+// Adjust stosuje zmianę odcienia, nasycenia i jasności do wszystkich pikseli
+func Adjust(img image.Image, a Adjustment) *image.RGBA {
+    return mapHSL(img, func(h, s, l float64) (float64, float64, float64) {
+        return applyAdjustment(h, s, l, a, 1)
+    })
+}
+
+// AdjustRange stosuje zmianę tylko do pikseli o odcieniu z zakresu r, z płynnym przejściem na brzegach.
+// Piksele szare (nasycenie 0) nie mają odcienia i nie są zmieniane.
+func AdjustRange(img image.Image, r HueRange, a Adjustment) *image.RGBA {
+    return mapHSL(img, func(h, s, l float64) (float64, float64, float64) {
+        if s == 0 {
+            return h, s, l
+        }
+        return applyAdjustment(h, s, l, a, rangeWeight(h, r))
+    })
+}
+
+// Colorize nadaje obrazowi jeden odcień hue (stopnie) o nasyceniu saturation (0..1), zachowując jasność L
+func Colorize(img image.Image, hue, saturation float64) *image.RGBA {
+    hue = wrapHue(hue)
+    saturation = clamp01(saturation)
+    return mapHSL(img, func(_, _, l float64) (float64, float64, float64) {
+        return hue, saturation, l
+    })
+}
+
+// applyAdjustment stosuje zmianę z wagą weight (0 - brak zmiany, 1 - pełna zmiana).
+// Nasycenie i jasność są liczone z wartości wejściowych i przycinane jeden raz, na końcu.
+func applyAdjustment(h, s, l float64, a Adjustment, weight float64) (float64, float64, float64) {
+    if weight <= 0 {
+        return h, s, l
+    }
+    weight = math.Min(weight, 1)
+    newS := s * (1 + a.Saturation*weight)
+    newL := l + a.Lightness*weight
+    return wrapHue(h + a.Hue*weight), clamp01(newS), clamp01(newL)
+}
+
+// hueDistance - najkrótsza odległość między odcieniami na kole barw (0..180), także przez 0/360
+func hueDistance(a, b float64) float64 {
+    d := wrapHue(a - b)
+    return math.Min(d, 360-d)
+}
+
+// rangeWeight - wpływ korekty selektywnej dla odcienia h (gładkie przejście smoothstep).
+// Środek zakresu może leżeć przy 0/360 (np. czerwienie) - odległość liczona jest modulo 360.
+func rangeWeight(h float64, r HueRange) float64 {
+    d := hueDistance(h, r.Center)
+    inner := r.Width / 2
+    if d <= inner {
+        return 1
+    }
+    if r.Falloff <= 0 || d >= inner+r.Falloff {
+        return 0
+    }
+    t := 1 - (d-inner)/r.Falloff
+    return t * t * (3 - 2*t)
+}
+
+// mapHSL przelicza każdy piksel przez f w przestrzeni HSL; kanał alfa zostaje zachowany.
+// Powrót do RGB zaokrągla wartości, więc przejście bez zmian nie przyciemnia obrazu.
+func mapHSL(img image.Image, f func(h, s, l float64) (float64, float64, float64)) *image.RGBA {
+    bounds := img.Bounds()
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            r, g, b := hslToRoundedRGB(f(RGBToHSL(c.R, c.G, c.B)))
+            out.Set(x, y, color.NRGBA{R: r, G: g, B: b, A: c.A})
+        }
+    }
+    return out
+}
+
+func wrapHue(h float64) float64 {
+    h = math.Mod(h, 360)
+    if h < 0 {
+        h += 360
+    }
+    return h
+}
+
+func clamp01(v float64) float64 {
+    return math.Max(0, math.Min(1, v))
+}
@@ -1,3 +1,7 @@
+// Package hsl konwertuje obrazy między RGB a HSL oraz zmienia odcień, nasycenie i jasność.
+// Alfa: HSL liczone jest z koloru bez premultiplikacji; macierz HSL nie przechowuje alfy,
+// więc obraz odtworzony przez ConvertHSLToRGBImage jest w pełni nieprzezroczysty.
+// Korekty (Adjust, Colorize itd.) zachowują kanał alfa.
 package hsl
 
 import (
@@ -53,8 +57,8 @@ func ConvertImageToHSL(img image.Image) [][][3]float64 {
     for y := 0; y < height; y++ {
         hslImage[y] = make([][3]float64, width)
         for x := 0; x < width; x++ {
-            r, g, b, _ := img.At(x, y).RGBA()
-            h, s, l := RGBToHSL(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            h, s, l := RGBToHSL(c.R, c.G, c.B)
             hslImage[y][x] = [3]float64{h, s, l}
         }
     }
@@ -63,6 +67,22 @@ func ConvertImageToHSL(img image.Image) [][][3]float64 {
 }
 
 func HSLToRGB(h, s, l float64) (uint8, uint8, uint8) {
+    rf, gf, bf := hslToUnitRGB(h, s, l)
+    r := uint8(rf * 255)
+    g := uint8(gf * 255)
+    b := uint8(bf * 255)
+    return r, g, b
+}
+
+// hslToRoundedRGB - jak HSLToRGB, ale z zaokrągleniem zamiast obcinania, dzięki czemu
+// przejście RGB -> HSL -> RGB nie zmienia koloru (używane przez korekty z adjust.go)
+func hslToRoundedRGB(h, s, l float64) (uint8, uint8, uint8) {
+    rf, gf, bf := hslToUnitRGB(h, s, l)
+    return uint8(math.Round(rf * 255)), uint8(math.Round(gf * 255)), uint8(math.Round(bf * 255))
+}
+
+// hslToUnitRGB zamienia HSL na składowe R, G, B z zakresu 0..1
+func hslToUnitRGB(h, s, l float64) (float64, float64, float64) {
     c := (1 - math.Abs(2*l-1)) * s
     x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
     m := l - c/2
@@ -82,11 +102,7 @@ func HSLToRGB(h, s, l float64) (uint8, uint8, uint8) {
     default:
         rf, gf, bf = c, 0, x
     }
-
-    r := uint8((rf + m) * 255)
-    g := uint8((gf + m) * 255)
-    b := uint8((bf + m) * 255)
-    return r, g, b
+    return rf + m, gf + m, bf + m
 }
 
 func ConvertHSLToRGBImage(hslImage [][][3]float64) image.Image {
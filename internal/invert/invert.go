@@ -1,3 +1,5 @@
+// Package invert odwraca kolory obrazu.
+// Alfa: odwracany jest kolor bez premultiplikacji, kanał alfa zostaje zachowany.
 package invert
 
 import (
@@ -15,9 +17,9 @@ func ApplyColorInversionToImage(img image.Image) *image.RGBA {
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			r, g, b, _ := img.At(x, y).RGBA()
-			invertedR, invertedG, invertedB := InvertColor(uint8(r>>8), uint8(g>>8), uint8(b>>8))
-			invertedColor := color.RGBA{R: invertedR, G: invertedG, B: invertedB, A: 255}
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			invertedR, invertedG, invertedB := InvertColor(c.R, c.G, c.B)
+			invertedColor := color.NRGBA{R: invertedR, G: invertedG, B: invertedB, A: c.A}
 			invertedImg.Set(x, y, invertedColor)
 		}
 	}
@@ -0,0 +1,198 @@
+package morphology
+
+import (
+    "image"
+    "image/color"
+    "math"
+)
+
+type DistanceMetric string
+
+const (
+    Euclidean  DistanceMetric = "euclidean"
+    CityBlock  DistanceMetric = "cityblock"
+    Chessboard DistanceMetric = "chessboard"
+)
+
+// DistanceTransform liczy dla każdego piksela obiektu (1) odległość do najbliższego piksela tła (0).
+// Piksele tła mają odległość 0. Gdy w macierzy nie ma tła, wynik wynosi +Inf.
+func DistanceTransform(bin [][]uint8, metric DistanceMetric) [][]float64 {
+    switch metric {
+    case CityBlock, Chessboard:
+        return chamferDistance(bin, metric)
+    default:
+        return euclideanDistance(bin)
+    }
+}
+
+// euclideanDistance - dokładna transformata euklidesowa (Felzenszwalb-Huttenlocher),
+// najpierw po kolumnach, potem po wierszach
+func euclideanDistance(bin [][]uint8) [][]float64 {
+    h, w := len(bin), len(bin[0])
+    n := h
+    if w > n {
+        n = w
+    }
+    f := make([]float64, n)
+    d := make([]float64, n)
+    v := make([]int, n)
+    z := make([]float64, n+1)
+
+    out := make([][]float64, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            if bin[y][x] == 0 {
+                out[y][x] = 0
+            } else {
+                out[y][x] = math.Inf(1)
+            }
+        }
+    }
+
+    for x := 0; x < w; x++ {
+        for y := 0; y < h; y++ {
+            f[y] = out[y][x]
+        }
+        squaredDistance1D(f[:h], d[:h], v, z)
+        for y := 0; y < h; y++ {
+            out[y][x] = d[y]
+        }
+    }
+    for y := 0; y < h; y++ {
+        copy(f[:w], out[y])
+        squaredDistance1D(f[:w], d[:w], v, z)
+        for x := 0; x < w; x++ {
+            out[y][x] = math.Sqrt(d[x])
+        }
+    }
+    return out
+}
+
+// squaredDistance1D - dolna obwiednia parabol dla jednej linii (kwadraty odległości)
+func squaredDistance1D(f, d []float64, v []int, z []float64) {
+    n := len(f)
+    k := -1
+    for q := 0; q < n; q++ {
+        if math.IsInf(f[q], 1) {
+            continue
+        }
+        for k >= 0 {
+            s := intersection(f, v[k], q)
+            if s > z[k] {
+                break
+            }
+            k--
+        }
+        k++
+        v[k] = q
+        if k == 0 {
+            z[k] = math.Inf(-1)
+        } else {
+            z[k] = intersection(f, v[k-1], q)
+        }
+        z[k+1] = math.Inf(1)
+    }
+    if k < 0 {
+        for q := 0; q < n; q++ {
+            d[q] = math.Inf(1)
+        }
+        return
+    }
+    j := 0
+    for q := 0; q < n; q++ {
+        for z[j+1] < float64(q) {
+            j++
+        }
+        dq := float64(q - v[j])
+        d[q] = dq*dq + f[v[j]]
+    }
+}
+
+// intersection - punkt przecięcia parabol zaczepionych w p i q
+func intersection(f []float64, p, q int) float64 {
+    fp, fq := float64(p), float64(q)
+    return ((f[q] + fq*fq) - (f[p] + fp*fp)) / (2*fq - 2*fp)
+}
+
+// chamferDistance - dwuprzebiegowa transformata, dokładna dla metryk miejskiej i szachowej
+func chamferDistance(bin [][]uint8, metric DistanceMetric) [][]float64 {
+    h, w := len(bin), len(bin[0])
+    out := make([][]float64, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            if bin[y][x] != 0 {
+                out[y][x] = math.Inf(1)
+            }
+        }
+    }
+    diagonal := metric == Chessboard
+
+    // Przebieg w przód: sąsiedzi z góry i z lewej
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            best := out[y][x]
+            if y > 0 {
+                best = math.Min(best, out[y-1][x]+1)
+                if diagonal && x > 0 {
+                    best = math.Min(best, out[y-1][x-1]+1)
+                }
+                if diagonal && x < w-1 {
+                    best = math.Min(best, out[y-1][x+1]+1)
+                }
+            }
+            if x > 0 {
+                best = math.Min(best, out[y][x-1]+1)
+            }
+            out[y][x] = best
+        }
+    }
+    // Przebieg wstecz: sąsiedzi z dołu i z prawej
+    for y := h - 1; y >= 0; y-- {
+        for x := w - 1; x >= 0; x-- {
+            best := out[y][x]
+            if y < h-1 {
+                best = math.Min(best, out[y+1][x]+1)
+                if diagonal && x < w-1 {
+                    best = math.Min(best, out[y+1][x+1]+1)
+                }
+                if diagonal && x > 0 {
+                    best = math.Min(best, out[y+1][x-1]+1)
+                }
+            }
+            if x < w-1 {
+                best = math.Min(best, out[y][x+1]+1)
+            }
+            out[y][x] = best
+        }
+    }
+    return out
+}
+
+// DistanceMatrixToImage skaluje mapę odległości do zakresu 0..255 (wartości +Inf stają się białe)
+func DistanceMatrixToImage(dist [][]float64) *image.Gray {
+    h := len(dist)
+    w := len(dist[0])
+    max := 0.0
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            if !math.IsInf(dist[y][x], 1) && dist[y][x] > max {
+                max = dist[y][x]
+            }
+        }
+    }
+    img := image.NewGray(image.Rect(0, 0, w, h))
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            val := uint8(0)
+            if math.IsInf(dist[y][x], 1) {
+                val = 255
+            } else if max > 0 {
+                val = uint8(math.Round(dist[y][x] / max * 255))
+            }
+            img.SetGray(x, y, color.Gray{Y: val})
+        }
+    }
+    return img
+}
@@ -0,0 +1,189 @@
+package morphology
+
+// DontCare - komórka szablonu, której wartość nie ma znaczenia.
+// Szablon hit-or-miss to jedna macierz: 1 = piksel obiektu, 0 = piksel tła, DontCare = dowolny.
+const DontCare = -1
+
+// HitOrMissTemplate - transformacja hit-or-miss dla jednego szablonu 1/0/DontCare.
+// Piksele spoza obrazu traktowane są jak tło, więc szablon działa też przy krawędziach.
+func HitOrMissTemplate(bin [][]uint8, template [][]int) [][]uint8 {
+    h, w := len(bin), len(bin[0])
+    kh, kw := len(template), len(template[0])
+    cy, cx := kh/2, kw/2
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        for x := 0; x < w; x++ {
+            hit := true
+            for ky := 0; ky < kh && hit; ky++ {
+                for kx := 0; kx < kw; kx++ {
+                    t := template[ky][kx]
+                    if t == DontCare {
+                        continue
+                    }
+                    iy, ix := y+ky-cy, x+kx-cx
+                    val := 0
+                    if iy >= 0 && iy < h && ix >= 0 && ix < w && bin[iy][ix] != 0 {
+                        val = 1
+                    }
+                    if val != t {
+                        hit = false
+                        break
+                    }
+                }
+            }
+            if hit {
+                out[y][x] = 1
+            }
+        }
+    }
+    return out
+}
+
+// HitOrMissFamily - suma wyników hit-or-miss dla rodziny szablonów
+func HitOrMissFamily(bin [][]uint8, templates [][][]int) [][]uint8 {
+    h, w := len(bin), len(bin[0])
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+    }
+    for _, template := range templates {
+        res := HitOrMissTemplate(bin, template)
+        for y := 0; y < h; y++ {
+            for x := 0; x < w; x++ {
+                out[y][x] |= res[y][x]
+            }
+        }
+    }
+    return out
+}
+
+// RotateTemplate obraca szablon o 90 stopni zgodnie z ruchem wskazówek zegara
+func RotateTemplate(template [][]int) [][]int {
+    h, w := len(template), len(template[0])
+    out := make([][]int, w)
+    for y := 0; y < w; y++ {
+        out[y] = make([]int, h)
+    }
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            out[x][h-1-y] = template[y][x]
+        }
+    }
+    return out
+}
+
+// Pierścień szablonu 3x3 zgodnie z ruchem wskazówek zegara
+var ring3x3 = [8][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {2, 1}, {2, 0}, {1, 0}}
+
+// RotateTemplate45 obraca szablon 3x3 o 45 stopni (przesuwa pierścień sąsiadów o jedną pozycję)
+func RotateTemplate45(template [][]int) [][]int {
+    out := [][]int{make([]int, 3), make([]int, 3), make([]int, 3)}
+    out[1][1] = template[1][1]
+    for i, p := range ring3x3 {
+        q := ring3x3[(i+1)%8]
+        out[q[0]][q[1]] = template[p[0]][p[1]]
+    }
+    return out
+}
+
+// TemplateRotations zwraca 4 (co 90°) lub 8 (co 45°, tylko szablony 3x3) obróconych wariantów,
+// bez powtórzeń dla szablonów symetrycznych
+func TemplateRotations(template [][]int, n int) [][][]int {
+    rotate := RotateTemplate
+    if n == 8 && len(template) == 3 && len(template[0]) == 3 {
+        rotate = RotateTemplate45
+    } else {
+        n = 4
+    }
+    out := [][][]int{}
+    current := template
+    for i := 0; i < n; i++ {
+        duplicate := false
+        for _, t := range out {
+            if equalTemplates(t, current) {
+                duplicate = true
+                break
+            }
+        }
+        if !duplicate {
+            out = append(out, current)
+        }
+        current = rotate(current)
+    }
+    return out
+}
+
+// EndpointTemplates - końce linii szkieletu
+func EndpointTemplates() [][][]int {
+    out := TemplateRotations([][]int{
+        {-1, 0, 0},
+        {1, 1, 0},
+        {-1, 0, 0},
+    }, 4)
+    return append(out, TemplateRotations([][]int{
+        {1, 0, 0},
+        {0, 1, 0},
+        {0, 0, 0},
+    }, 4)...)
+}
+
+// JunctionTemplates - punkty rozgałęzień szkieletu (kształty T i Y)
+func JunctionTemplates() [][][]int {
+    out := TemplateRotations([][]int{
+        {-1, -1, -1},
+        {1, 1, 1},
+        {-1, 1, -1},
+    }, 8)
+    return append(out, TemplateRotations([][]int{
+        {1, -1, 1},
+        {-1, 1, -1},
+        {-1, 1, -1},
+    }, 8)...)
+}
+
+// CornerTemplates - narożniki obiektów
+func CornerTemplates() [][][]int {
+    return TemplateRotations([][]int{
+        {-1, 1, -1},
+        {0, 1, 1},
+        {0, 0, -1},
+    }, 4)
+}
+
+// IsolatedPixelTemplates - pojedyncze piksele bez sąsiadów
+func IsolatedPixelTemplates() [][][]int {
+    return [][][]int{{
+        {0, 0, 0},
+        {0, 1, 0},
+        {0, 0, 0},
+    }}
+}
+
+// FindEndpoints zaznacza końce linii szkieletu
+func FindEndpoints(skeleton [][]uint8) [][]uint8 {
+    return HitOrMissFamily(skeleton, EndpointTemplates())
+}
+
+// FindJunctions zaznacza punkty rozgałęzień szkieletu
+func FindJunctions(skeleton [][]uint8) [][]uint8 {
+    return HitOrMissFamily(skeleton, JunctionTemplates())
+}
+
+// Pomocnicza: porównuje dwa szablony
+func equalTemplates(a, b [][]int) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for y := range a {
+        if len(a[y]) != len(b[y]) {
+            return false
+        }
+        for x := range a[y] {
+            if a[y][x] != b[y][x] {
+                return false
+            }
+        }
+    }
+    return true
+}
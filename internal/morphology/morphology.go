@@ -1,3 +1,5 @@
+// Package morphology wykonuje operacje morfologiczne na macierzach binarnych.
+// Alfa: ImageToBinaryMatrix pomija kanał alfa; obrazy wynikowe są nieprzezroczyste.
 package morphology
 
 import (
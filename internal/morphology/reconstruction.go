@@ -0,0 +1,302 @@
+package morphology
+
+import "math"
+
+var neighbours8 = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+
+// GeodesicDilate - dylatacja markera ograniczona maską (min(dylatacja, maska))
+func GeodesicDilate(marker, mask [][]uint8, kernel [][]int) [][]uint8 {
+    out := Dilate(marker, kernel)
+    for y := range out {
+        for x := range out[y] {
+            if mask[y][x] == 0 {
+                out[y][x] = 0
+            }
+        }
+    }
+    return out
+}
+
+// GeodesicErode - erozja markera ograniczona maską od dołu (max(erozja, maska))
+func GeodesicErode(marker, mask [][]uint8, kernel [][]int) [][]uint8 {
+    out := Erode(marker, kernel)
+    for y := range out {
+        for x := range out[y] {
+            if mask[y][x] == 1 {
+                out[y][x] = 1
+            }
+        }
+    }
+    return out
+}
+
+// ReconstructByDilation - rekonstrukcja binarna: obiekty maski (8-sąsiedztwo), których dotyka marker
+func ReconstructByDilation(marker, mask [][]uint8) [][]uint8 {
+    h, w := len(mask), len(mask[0])
+    out := make([][]uint8, h)
+    stack := [][2]int{}
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        for x := 0; x < w; x++ {
+            if marker[y][x] == 1 && mask[y][x] == 1 {
+                out[y][x] = 1
+                stack = append(stack, [2]int{y, x})
+            }
+        }
+    }
+    for len(stack) > 0 {
+        p := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        for _, n := range neighbours8 {
+            iy, ix := p[0]+n[0], p[1]+n[1]
+            if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                continue
+            }
+            if mask[iy][ix] == 1 && out[iy][ix] == 0 {
+                out[iy][ix] = 1
+                stack = append(stack, [2]int{iy, ix})
+            }
+        }
+    }
+    return out
+}
+
+// ReconstructByErosion - rekonstrukcja binarna przez erozję (dualna do rekonstrukcji przez dylatację)
+func ReconstructByErosion(marker, mask [][]uint8) [][]uint8 {
+    out := ReconstructByDilation(complement(marker), complement(mask))
+    return complement(out)
+}
+
+// GrayReconstructByDilation - rekonstrukcja w skali szarości (algorytm hybrydowy Vincenta).
+// Marker jest najpierw przycinany do maski.
+func GrayReconstructByDilation(marker, mask [][]float64) [][]float64 {
+    h, w := len(mask), len(mask[0])
+    out := make([][]float64, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            out[y][x] = math.Min(marker[y][x], mask[y][x])
+        }
+    }
+
+    // Przebieg w przód (sąsiedzi już odwiedzeni: góra i lewo)
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            v := out[y][x]
+            for _, n := range neighbours8[:4] {
+                iy, ix := y+n[0], x+n[1]
+                if iy >= 0 && ix >= 0 && ix < w {
+                    v = math.Max(v, out[iy][ix])
+                }
+            }
+            out[y][x] = math.Min(v, mask[y][x])
+        }
+    }
+
+    // Przebieg wstecz, piksele które mogą jeszcze propagować trafiają do kolejki
+    queue := [][2]int{}
+    for y := h - 1; y >= 0; y-- {
+        for x := w - 1; x >= 0; x-- {
+            v := out[y][x]
+            for _, n := range neighbours8[4:] {
+                iy, ix := y+n[0], x+n[1]
+                if iy < h && ix >= 0 && ix < w {
+                    v = math.Max(v, out[iy][ix])
+                }
+            }
+            out[y][x] = math.Min(v, mask[y][x])
+            for _, n := range neighbours8[4:] {
+                iy, ix := y+n[0], x+n[1]
+                if iy < h && ix >= 0 && ix < w && out[iy][ix] < out[y][x] && out[iy][ix] < mask[iy][ix] {
+                    queue = append(queue, [2]int{y, x})
+                    break
+                }
+            }
+        }
+    }
+
+    // Propagacja FIFO
+    for len(queue) > 0 {
+        p := queue[0]
+        queue = queue[1:]
+        for _, n := range neighbours8 {
+            iy, ix := p[0]+n[0], p[1]+n[1]
+            if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                continue
+            }
+            if out[iy][ix] < out[p[0]][p[1]] && out[iy][ix] != mask[iy][ix] {
+                out[iy][ix] = math.Min(out[p[0]][p[1]], mask[iy][ix])
+                queue = append(queue, [2]int{iy, ix})
+            }
+        }
+    }
+    return out
+}
+
+// GrayReconstructByErosion - rekonstrukcja w skali szarości przez erozję (marker przycinany od dołu do maski)
+func GrayReconstructByErosion(marker, mask [][]float64) [][]float64 {
+    out := GrayReconstructByDilation(negate(marker), negate(mask))
+    return negate(out)
+}
+
+// FillHoles wypełnia dziury, czyli tło nieosiągalne od krawędzi obrazu
+func FillHoles(bin [][]uint8) [][]uint8 {
+    background := complement(bin)
+    reached := ReconstructByDilation(borderOf(background), background)
+    h, w := len(bin), len(bin[0])
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        for x := 0; x < w; x++ {
+            if bin[y][x] == 1 || reached[y][x] == 0 {
+                out[y][x] = 1
+            }
+        }
+    }
+    return out
+}
+
+// ClearBorder usuwa obiekty dotykające krawędzi obrazu
+func ClearBorder(bin [][]uint8) [][]uint8 {
+    touching := ReconstructByDilation(borderOf(bin), bin)
+    h, w := len(bin), len(bin[0])
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        for x := 0; x < w; x++ {
+            if bin[y][x] == 1 && touching[y][x] == 0 {
+                out[y][x] = 1
+            }
+        }
+    }
+    return out
+}
+
+// SuppressMaxima - transformata h-maksimów: wygasza maksima o wysokości nie większej niż h
+func SuppressMaxima(gray [][]float64, h float64) [][]float64 {
+    marker := make([][]float64, len(gray))
+    for y := range gray {
+        marker[y] = make([]float64, len(gray[y]))
+        for x := range gray[y] {
+            marker[y][x] = gray[y][x] - h
+        }
+    }
+    return GrayReconstructByDilation(marker, gray)
+}
+
+// SuppressMinima - transformata h-minimów: wypełnia minima o głębokości nie większej niż h
+func SuppressMinima(gray [][]float64, h float64) [][]float64 {
+    marker := make([][]float64, len(gray))
+    for y := range gray {
+        marker[y] = make([]float64, len(gray[y]))
+        for x := range gray[y] {
+            marker[y][x] = gray[y][x] + h
+        }
+    }
+    return GrayReconstructByErosion(marker, gray)
+}
+
+// HMaxima zaznacza maksima wyższe niż h (maksima regionalne po SuppressMaxima)
+func HMaxima(gray [][]float64, h float64) [][]uint8 {
+    return RegionalMaxima(SuppressMaxima(gray, h))
+}
+
+// HMinima zaznacza minima głębsze niż h (minima regionalne po SuppressMinima)
+func HMinima(gray [][]float64, h float64) [][]uint8 {
+    return RegionalMinima(SuppressMinima(gray, h))
+}
+
+// RegionalMaxima zaznacza płaskie obszary (8-sąsiedztwo), których wszyscy sąsiedzi są niżej
+func RegionalMaxima(gray [][]float64) [][]uint8 {
+    return regionalExtrema(gray, func(neighbour, plateau float64) bool { return neighbour > plateau })
+}
+
+// RegionalMinima zaznacza płaskie obszary (8-sąsiedztwo), których wszyscy sąsiedzi są wyżej
+func RegionalMinima(gray [][]float64) [][]uint8 {
+    return regionalExtrema(gray, func(neighbour, plateau float64) bool { return neighbour < plateau })
+}
+
+// regionalExtrema - przeszukuje każdy płaski obszar; odrzuca go, gdy jakiś sąsiad "wygrywa"
+func regionalExtrema(gray [][]float64, beats func(neighbour, plateau float64) bool) [][]uint8 {
+    h, w := len(gray), len(gray[0])
+    out := make([][]uint8, h)
+    visited := make([][]bool, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        visited[y] = make([]bool, w)
+    }
+    plateau := [][2]int{}
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            if visited[y][x] {
+                continue
+            }
+            value := gray[y][x]
+            extremum := true
+            visited[y][x] = true
+            plateau = append(plateau[:0], [2]int{y, x})
+            for i := 0; i < len(plateau); i++ {
+                p := plateau[i]
+                for _, n := range neighbours8 {
+                    iy, ix := p[0]+n[0], p[1]+n[1]
+                    if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                        continue
+                    }
+                    if gray[iy][ix] == value {
+                        if !visited[iy][ix] {
+                            visited[iy][ix] = true
+                            plateau = append(plateau, [2]int{iy, ix})
+                        }
+                    } else if beats(gray[iy][ix], value) {
+                        extremum = false
+                    }
+                }
+            }
+            if extremum {
+                for _, p := range plateau {
+                    out[p[0]][p[1]] = 1
+                }
+            }
+        }
+    }
+    return out
+}
+
+// Pomocnicza: dopełnienie macierzy binarnej
+func complement(bin [][]uint8) [][]uint8 {
+    out := make([][]uint8, len(bin))
+    for y := range bin {
+        out[y] = make([]uint8, len(bin[y]))
+        for x := range bin[y] {
+            out[y][x] = 1 - bin[y][x]&1
+        }
+    }
+    return out
+}
+
+// Pomocnicza: zostawia tylko piksele leżące na krawędzi obrazu
+func borderOf(bin [][]uint8) [][]uint8 {
+    h, w := len(bin), len(bin[0])
+    out := make([][]uint8, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]uint8, w)
+        for x := 0; x < w; x++ {
+            if y == 0 || y == h-1 || x == 0 || x == w-1 {
+                out[y][x] = bin[y][x]
+            }
+        }
+    }
+    return out
+}
+
+// Pomocnicza: neguje macierz
+func negate(mat [][]float64) [][]float64 {
+    out := make([][]float64, len(mat))
+    for y := range mat {
+        out[y] = make([]float64, len(mat[y]))
+        for x := range mat[y] {
+            out[y][x] = -mat[y][x]
+        }
+    }
+    return out
+}
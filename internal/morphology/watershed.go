@@ -0,0 +1,209 @@
+package morphology
+
+import (
+    "container/heap"
+    "image"
+    "image/color"
+    "image-processing/v1/internal/hsl"
+    "math"
+)
+
+// WatershedLine - etykieta pikseli granicznych między zlewniami
+const WatershedLine = -1
+
+// LabelComponents nadaje etykiety 1..n spójnym (8-sąsiedztwo) obiektom macierzy binarnej.
+// Zwraca macierz etykiet (0 = tło) oraz liczbę obiektów.
+func LabelComponents(bin [][]uint8) ([][]int, int) {
+    h, w := len(bin), len(bin[0])
+    labels := make([][]int, h)
+    for y := 0; y < h; y++ {
+        labels[y] = make([]int, w)
+    }
+    count := 0
+    stack := [][2]int{}
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            if bin[y][x] == 0 || labels[y][x] != 0 {
+                continue
+            }
+            count++
+            labels[y][x] = count
+            stack = append(stack[:0], [2]int{y, x})
+            for len(stack) > 0 {
+                p := stack[len(stack)-1]
+                stack = stack[:len(stack)-1]
+                for dy := -1; dy <= 1; dy++ {
+                    for dx := -1; dx <= 1; dx++ {
+                        iy, ix := p[0]+dy, p[1]+dx
+                        if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                            continue
+                        }
+                        if bin[iy][ix] != 0 && labels[iy][ix] == 0 {
+                            labels[iy][ix] = count
+                            stack = append(stack, [2]int{iy, ix})
+                        }
+                    }
+                }
+            }
+        }
+    }
+    return labels, count
+}
+
+// Element kolejki priorytetowej zalewania
+type floodItem struct {
+    value float64
+    order int
+    y, x  int
+}
+
+type floodQueue []floodItem
+
+func (q floodQueue) Len() int { return len(q) }
+func (q floodQueue) Less(i, j int) bool {
+    if q[i].value != q[j].value {
+        return q[i].value < q[j].value
+    }
+    return q[i].order < q[j].order
+}
+func (q floodQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *floodQueue) Push(v interface{}) { *q = append(*q, v.(floodItem)) }
+func (q *floodQueue) Pop() interface{} {
+    old := *q
+    item := old[len(old)-1]
+    *q = old[:len(old)-1]
+    return item
+}
+
+var neighbours4 = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+// Watershed wykonuje segmentację wododziałową sterowaną markerami (zalewanie Meyera).
+// gray - obraz w skali szarości (np. moduł gradientu albo zanegowana mapa odległości)
+// markers - etykiety startowe (>0), 0 oznacza piksel do zalania
+// Zwraca macierz etykiet; piksele na styku dwóch zlewni dostają WatershedLine.
+func Watershed(gray [][]float64, markers [][]int) [][]int {
+    h, w := len(gray), len(gray[0])
+    labels := make([][]int, h)
+    queued := make([][]bool, h)
+    for y := 0; y < h; y++ {
+        labels[y] = make([]int, w)
+        copy(labels[y], markers[y])
+        queued[y] = make([]bool, w)
+    }
+
+    q := &floodQueue{}
+    order := 0
+    push := func(y, x int) {
+        queued[y][x] = true
+        heap.Push(q, floodItem{value: gray[y][x], order: order, y: y, x: x})
+        order++
+    }
+
+    // Start: piksele bez etykiety sąsiadujące z markerami
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            if labels[y][x] <= 0 {
+                continue
+            }
+            for _, n := range neighbours4 {
+                iy, ix := y+n[0], x+n[1]
+                if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                    continue
+                }
+                if labels[iy][ix] == 0 && !queued[iy][ix] {
+                    push(iy, ix)
+                }
+            }
+        }
+    }
+
+    for q.Len() > 0 {
+        item := heap.Pop(q).(floodItem)
+        y, x := item.y, item.x
+        label := 0
+        for _, n := range neighbours4 {
+            iy, ix := y+n[0], x+n[1]
+            if iy < 0 || iy >= h || ix < 0 || ix >= w || labels[iy][ix] <= 0 {
+                continue
+            }
+            if label == 0 {
+                label = labels[iy][ix]
+            } else if label != labels[iy][ix] {
+                label = WatershedLine
+                break
+            }
+        }
+        labels[y][x] = label
+        if label == WatershedLine {
+            continue
+        }
+        for _, n := range neighbours4 {
+            iy, ix := y+n[0], x+n[1]
+            if iy < 0 || iy >= h || ix < 0 || ix >= w {
+                continue
+            }
+            if labels[iy][ix] == 0 && !queued[iy][ix] {
+                push(iy, ix)
+            }
+        }
+    }
+    return labels
+}
+
+// labelColor - stały kolor dla etykiety (odcienie rozłożone złotym kątem)
+func labelColor(label int) color.RGBA {
+    if label == WatershedLine {
+        return color.RGBA{255, 0, 0, 255}
+    }
+    if label <= 0 {
+        return color.RGBA{0, 0, 0, 255}
+    }
+    hue := math.Mod(float64(label)*137.508, 360)
+    r, g, b := hsl.HSLToRGB(hue, 0.75, 0.55)
+    return color.RGBA{r, g, b, 255}
+}
+
+// LabelsToImage koloruje macierz etykiet (tło czarne, linie wododziałowe czerwone)
+func LabelsToImage(labels [][]int) *image.RGBA {
+    h := len(labels)
+    w := len(labels[0])
+    img := image.NewRGBA(image.Rect(0, 0, w, h))
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            img.SetRGBA(x, y, labelColor(labels[y][x]))
+        }
+    }
+    return img
+}
+
+// OverlayLabels nakłada pokolorowane etykiety na obraz.
+// alpha - krycie kolorów regionów (0..1); linie wododziałowe są zawsze w pełni kryjące.
+func OverlayLabels(img image.Image, labels [][]int, alpha float64) *image.RGBA {
+    bounds := img.Bounds()
+    w, h := bounds.Dx(), bounds.Dy()
+    alpha = math.Max(0, math.Min(1, alpha))
+    out := image.NewRGBA(image.Rect(0, 0, w, h))
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+            label := 0
+            if y < len(labels) && x < len(labels[y]) {
+                label = labels[y][x]
+            }
+            a := alpha
+            if label == WatershedLine {
+                a = 1
+            } else if label == 0 {
+                a = 0
+            }
+            c := labelColor(label)
+            out.SetRGBA(x, y, color.RGBA{
+                R: uint8(float64(r>>8)*(1-a) + float64(c.R)*a + 0.5),
+                G: uint8(float64(g>>8)*(1-a) + float64(c.G)*a + 0.5),
+                B: uint8(float64(b>>8)*(1-a) + float64(c.B)*a + 0.5),
+                A: 255,
+            })
+        }
+    }
+    return out
+}
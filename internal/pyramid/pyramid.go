@@ -0,0 +1,267 @@
+// Package pyramid buduje piramidy Gaussa i Laplace'a oraz łączy obrazy metodą wielopasmową.
+// Alfa: kanały są przechowywane jako wartości premultiplikowane, alfa jest filtrowana jak pozostałe kanały.
+package pyramid
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image-processing/v1/internal/scale"
+	"image/color"
+	"math"
+)
+
+// Planes - obraz jako cztery macierze kanałów R, G, B, A (premultiplikowane, 0..255).
+// Poziomy piramidy Laplace'a mogą zawierać wartości ujemne.
+type Planes [4][][]float64
+
+// FromImage rozkłada obraz na kanały
+func FromImage(img image.Image) Planes {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	var p Planes
+	for c := range p {
+		p[c] = make([][]float64, h)
+		for y := 0; y < h; y++ {
+			p[c][y] = make([]float64, w)
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			p[0][y][x] = float64(r) / 257
+			p[1][y][x] = float64(g) / 257
+			p[2][y][x] = float64(b) / 257
+			p[3][y][x] = float64(a) / 257
+		}
+	}
+	return p
+}
+
+// Size zwraca szerokość i wysokość poziomu (0, 0 dla pustego poziomu)
+func (p Planes) Size() (int, int) {
+	if len(p[0]) == 0 {
+		return 0, 0
+	}
+	return len(p[0][0]), len(p[0])
+}
+
+// ToImage składa kanały w obraz, przycinając wartości do zakresu
+func (p Planes) ToImage() *image.RGBA {
+	w, h := p.Size()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a := clamp(p[3][y][x], 255)
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8(clamp(p[0][y][x], a)),
+				G: uint8(clamp(p[1][y][x], a)),
+				B: uint8(clamp(p[2][y][x], a)),
+				A: uint8(a),
+			})
+		}
+	}
+	return out
+}
+
+// ToVisibleImage pokazuje poziom piramidy Laplace'a: wartości przesunięte o 128, pełne krycie
+func (p Planes) ToVisibleImage() *image.RGBA {
+	w, h := p.Size()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8(clamp(p[0][y][x]+128, 255)),
+				G: uint8(clamp(p[1][y][x]+128, 255)),
+				B: uint8(clamp(p[2][y][x]+128, 255)),
+				A: 255,
+			})
+		}
+	}
+	return out
+}
+
+// Gaussian buduje piramidę Gaussa: poziom 0 to oryginał, każdy kolejny jest rozmyty i zmniejszony dwukrotnie.
+// Budowa kończy się wcześniej, gdy poziom ma już mniej niż 2 piksele szerokości lub wysokości.
+func Gaussian(img image.Image, levels int) ([]Planes, error) {
+	if err := checkInput(img, levels); err != nil {
+		return nil, err
+	}
+	return gaussian(FromImage(img), levels), nil
+}
+
+// checkInput odrzuca pusty obraz i niedodatnią liczbę poziomów
+func checkInput(img image.Image, levels int) error {
+	if img.Bounds().Empty() {
+		return errors.New("cannot build a pyramid from an empty image")
+	}
+	if levels < 1 {
+		return fmt.Errorf("pyramid needs at least 1 level, got %d", levels)
+	}
+	return nil
+}
+
+func gaussian(base Planes, levels int) []Planes {
+	pyr := []Planes{base}
+	for i := 1; i < levels; i++ {
+		w, h := pyr[i-1].Size()
+		if w < 2 || h < 2 {
+			break
+		}
+		pyr = append(pyr, downsample(pyr[i-1]))
+	}
+	return pyr
+}
+
+// Laplacian buduje piramidę Laplace'a: poziom i to różnica poziomu Gaussa i powiększonego poziomu i+1,
+// ostatni poziom to najmniejszy poziom Gaussa (reszta)
+func Laplacian(img image.Image, levels int) ([]Planes, error) {
+	gauss, err := Gaussian(img, levels)
+	if err != nil {
+		return nil, err
+	}
+	return laplacian(gauss), nil
+}
+
+func laplacian(gauss []Planes) []Planes {
+	n := len(gauss)
+	pyr := make([]Planes, n)
+	for i := 0; i < n-1; i++ {
+		w, h := gauss[i].Size()
+		pyr[i] = subtract(gauss[i], upsample(gauss[i+1], w, h))
+	}
+	pyr[n-1] = gauss[n-1]
+	return pyr
+}
+
+// Reconstruct odtwarza obraz z piramidy Laplace'a
+func Reconstruct(lap []Planes) (*image.RGBA, error) {
+	if len(lap) == 0 {
+		return nil, errors.New("empty pyramid")
+	}
+	for i, level := range lap {
+		if w, h := level.Size(); w == 0 || h == 0 {
+			return nil, fmt.Errorf("pyramid level %d is empty", i)
+		}
+	}
+	return reconstruct(lap).ToImage(), nil
+}
+
+func reconstruct(lap []Planes) Planes {
+	cur := lap[len(lap)-1]
+	for i := len(lap) - 2; i >= 0; i-- {
+		w, h := lap[i].Size()
+		cur = add(lap[i], upsample(cur, w, h))
+	}
+	return cur
+}
+
+// Blend łączy dwa obrazy wielopasmowo: każdy poziom Laplace'a jest mieszany przez rozmytą
+// (piramida Gaussa) maskę. Biel maski wybiera obraz a, czerń obraz b.
+func Blend(a, b, mask image.Image, levels int) (*image.RGBA, error) {
+	if a.Bounds().Size() != b.Bounds().Size() || a.Bounds().Size() != mask.Bounds().Size() {
+		return nil, errors.New("images and mask must have the same size")
+	}
+	lapA, err := Laplacian(a, levels)
+	if err != nil {
+		return nil, err
+	}
+	lapB, err := Laplacian(b, levels)
+	if err != nil {
+		return nil, err
+	}
+	maskPyr := gaussian(maskPlanes(mask), len(lapA))
+
+	out := make([]Planes, len(lapA))
+	for i := range lapA {
+		w, h := lapA[i].Size()
+		m := maskPyr[i][0]
+		var p Planes
+		for c := range p {
+			p[c] = make([][]float64, h)
+			for y := 0; y < h; y++ {
+				p[c][y] = make([]float64, w)
+				for x := 0; x < w; x++ {
+					p[c][y][x] = m[y][x]*lapA[i][c][y][x] + (1-m[y][x])*lapB[i][c][y][x]
+				}
+			}
+		}
+		out[i] = p
+	}
+	return Reconstruct(out)
+}
+
+// maskPlanes zamienia maskę na wagi 0..1 (jasność) zapisane w pierwszym kanale
+func maskPlanes(mask image.Image) Planes {
+	p := FromImage(mask)
+	w, h := p.Size()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p[0][y][x] = (0.299*p[0][y][x] + 0.587*p[1][y][x] + 0.114*p[2][y][x]) / 255
+		}
+	}
+	p[1], p[2], p[3] = p[0], p[0], p[0]
+	return p
+}
+
+// downsample zmniejsza poziom dwukrotnie filtrem Gaussa z pakietu scale (rozmycie i decymacja w jednym kroku)
+func downsample(p Planes) Planes {
+	w, h := p.Size()
+	return resize(p, (w+1)/2, (h+1)/2)
+}
+
+// upsample powiększa poziom do rozmiaru w x h tym samym filtrem Gaussa
+func upsample(p Planes, w, h int) Planes {
+	return resize(p, w, h)
+}
+
+// resize przepuszcza kanały przez bufor RGBA pakietu scale, zachowując wartości ujemne i ułamkowe
+func resize(p Planes, w, h int) Planes {
+	sw, sh := p.Size()
+	pix := make([]float64, sw*sh*4)
+	for c := range p {
+		for y := 0; y < sh; y++ {
+			for x := 0; x < sw; x++ {
+				pix[(y*sw+x)*4+c] = p[c][y][x]
+			}
+		}
+	}
+	pix = scale.ResizeBuffer(pix, sw, sh, w, h, scale.Gaussian)
+	var out Planes
+	for c := range out {
+		out[c] = make([][]float64, h)
+		for y := 0; y < h; y++ {
+			out[c][y] = make([]float64, w)
+			for x := 0; x < w; x++ {
+				out[c][y][x] = pix[(y*w+x)*4+c]
+			}
+		}
+	}
+	return out
+}
+
+func subtract(a, b Planes) Planes {
+	return combine(a, b, -1)
+}
+
+func add(a, b Planes) Planes {
+	return combine(a, b, 1)
+}
+
+func combine(a, b Planes, sign float64) Planes {
+	var out Planes
+	for c := range a {
+		out[c] = make([][]float64, len(a[c]))
+		for y := range a[c] {
+			out[c][y] = make([]float64, len(a[c][y]))
+			for x := range a[c][y] {
+				out[c][y][x] = a[c][y][x] + sign*b[c][y][x]
+			}
+		}
+	}
+	return out
+}
+
+func clamp(v, max float64) float64 {
+	return math.Round(math.Max(0, math.Min(max, v)))
+}
@@ -0,0 +1,41 @@
+package quantize
+
+// kmeans poprawia paletę początkową algorytmem Lloyda: każdy kolor obrazu przypisywany jest
+// do najbliższego środka, a środki przesuwane do średniej ważonej przypisanych kolorów
+func kmeans(colors []weightedColor, centers [][3]int) [][3]int {
+	if len(centers) == 0 {
+		return centers
+	}
+	assignment := make([]int, len(colors))
+	for iter := 0; iter < kmeansIterations; iter++ {
+		changed := iter == 0
+		for i, c := range colors {
+			if idx := nearest(centers, c.rgb, -1); idx != assignment[i] {
+				assignment[i] = idx
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][3]int, len(centers))
+		counts := make([]int, len(centers))
+		for i, c := range colors {
+			idx := assignment[i]
+			for k := range c.rgb {
+				sums[idx][k] += c.rgb[k] * c.count
+			}
+			counts[idx] += c.count
+		}
+		for i := range centers {
+			// Pusty klaster zachowuje poprzedni środek
+			if counts[i] > 0 {
+				for k := range centers[i] {
+					centers[i][k] = (sums[i][k] + counts[i]/2) / counts[i]
+				}
+			}
+		}
+	}
+	return centers
+}
@@ -0,0 +1,77 @@
+package quantize
+
+import "sort"
+
+// box - pudełko kolorów w algorytmie median cut
+type box struct {
+	colors []weightedColor
+	count  int
+	lo, hi [3]int
+}
+
+func newBox(colors []weightedColor) *box {
+	b := &box{colors: colors, lo: [3]int{255, 255, 255}}
+	for _, c := range colors {
+		b.count += c.count
+		for k := range c.rgb {
+			b.lo[k] = min(b.lo[k], c.rgb[k])
+			b.hi[k] = max(b.hi[k], c.rgb[k])
+		}
+	}
+	return b
+}
+
+// longestAxis - kanał o największym rozrzucie w pudełku i ten rozrzut
+func (b *box) longestAxis() (int, int) {
+	axis := 0
+	for k := 1; k < 3; k++ {
+		if b.hi[k]-b.lo[k] > b.hi[axis]-b.lo[axis] {
+			axis = k
+		}
+	}
+	return axis, b.hi[axis] - b.lo[axis]
+}
+
+// medianCut dzieli przestrzeń kolorów na n pudełek. W każdym kroku dzielone jest pudełko
+// o największym iloczynie rozrzutu i liczby pikseli, wzdłuż najdłuższej osi, w ważonej medianie.
+func medianCut(colors []weightedColor, n int) [][3]int {
+	if n <= 0 || len(colors) == 0 {
+		return nil
+	}
+	boxes := []*box{newBox(colors)}
+	for len(boxes) < n {
+		best, bestScore := -1, 0
+		for i, b := range boxes {
+			if len(b.colors) < 2 {
+				continue
+			}
+			_, spread := b.longestAxis()
+			if score := spread * b.count; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break // każde pudełko ma już jeden kolor
+		}
+
+		b := boxes[best]
+		axis, _ := b.longestAxis()
+		sort.Slice(b.colors, func(i, j int) bool { return b.colors[i].rgb[axis] < b.colors[j].rgb[axis] })
+		half, sum, split := b.count/2, 0, 1
+		for i, c := range b.colors[:len(b.colors)-1] {
+			sum += c.count
+			split = i + 1
+			if sum >= half {
+				break
+			}
+		}
+		boxes[best] = newBox(b.colors[:split])
+		boxes = append(boxes, newBox(b.colors[split:]))
+	}
+
+	centers := make([][3]int, len(boxes))
+	for i, b := range boxes {
+		centers[i] = mean(b.colors)
+	}
+	return centers
+}
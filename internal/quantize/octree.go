@@ -0,0 +1,94 @@
+package quantize
+
+import "sort"
+
+// Głębokość drzewa - jeden poziom na każdy bit kanału
+const octreeDepth = 8
+
+type octreeNode struct {
+	children [8]*octreeNode
+	sum      [3]int
+	count    int
+	leaf     bool
+}
+
+// octree buduje drzewo ósemkowe kolorów i łączy liście, zaczynając od najgłębszych węzłów
+// o najmniejszej liczbie pikseli, aż zostanie co najwyżej n liści
+func octree(colors []weightedColor, n int) [][3]int {
+	if n <= 0 || len(colors) == 0 {
+		return nil
+	}
+	root := &octreeNode{}
+	levels := make([][]*octreeNode, octreeDepth)
+	leaves := 0
+	for _, c := range colors {
+		node := root
+		for depth := 0; depth < octreeDepth; depth++ {
+			for k := range node.sum {
+				node.sum[k] += c.rgb[k] * c.count
+			}
+			node.count += c.count
+			idx := octreeIndex(c.rgb, depth)
+			if node.children[idx] == nil {
+				node.children[idx] = &octreeNode{}
+				if depth < octreeDepth-1 {
+					levels[depth+1] = append(levels[depth+1], node.children[idx])
+				} else {
+					leaves++
+				}
+			}
+			node = node.children[idx]
+		}
+		for k := range node.sum {
+			node.sum[k] += c.rgb[k] * c.count
+		}
+		node.count += c.count
+		node.leaf = true
+	}
+	levels[0] = []*octreeNode{root}
+
+	for depth := octreeDepth - 1; depth >= 0 && leaves > n; depth-- {
+		nodes := levels[depth]
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].count < nodes[j].count })
+		for _, node := range nodes {
+			if leaves <= n {
+				break
+			}
+			children := 0
+			for i, child := range node.children {
+				if child != nil {
+					children++
+					node.children[i] = nil
+				}
+			}
+			node.leaf = true
+			leaves -= children - 1
+		}
+	}
+
+	centers := [][3]int{}
+	var collect func(node *octreeNode)
+	collect = func(node *octreeNode) {
+		if node.leaf {
+			centers = append(centers, [3]int{
+				(node.sum[0] + node.count/2) / node.count,
+				(node.sum[1] + node.count/2) / node.count,
+				(node.sum[2] + node.count/2) / node.count,
+			})
+			return
+		}
+		for _, child := range node.children {
+			if child != nil {
+				collect(child)
+			}
+		}
+	}
+	collect(root)
+	return centers
+}
+
+// octreeIndex - numer dziecka na danej głębokości, z kolejnych bitów R, G i B
+func octreeIndex(c [3]int, depth int) int {
+	shift := 7 - depth
+	return (c[0]>>shift&1)<<2 | (c[1]>>shift&1)<<1 | c[2]>>shift&1
+}
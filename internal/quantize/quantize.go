@@ -0,0 +1,243 @@
+// Package quantize zmniejsza liczbę kolorów obrazu do palety N kolorów (median cut, octree, k-means)
+// i zwraca obraz z paletą (*image.Paletted), gotowy do zapisu jako GIF lub PNG-8.
+// Alfa: piksele o alfie < 128 trafiają do przezroczystego koloru palety, pozostałe są traktowane
+// jako nieprzezroczyste (kolor bez premultiplikacji).
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Method string
+
+const (
+	MedianCut Method = "mediancut" // podział pudełek kolorów wzdłuż najdłuższej osi
+	Octree    Method = "octree"    // łączenie liści drzewa ósemkowego
+	KMeans    Method = "kmeans"    // algorytm Lloyda startujący z palety median cut
+)
+
+// Liczba iteracji k-means
+const kmeansIterations = 10
+
+// Transparent - kolor palety dla przezroczystych pikseli
+var Transparent = color.NRGBA{}
+
+// weightedColor - jeden z kolorów obrazu i liczba pikseli, które go mają
+type weightedColor struct {
+	rgb   [3]int
+	count int
+}
+
+// Palette wyznacza paletę co najwyżej n kolorów (1..256). Jeśli obraz ma przezroczyste piksele,
+// jedno miejsce palety zajmuje kolor Transparent - wtedy n musi wynosić co najmniej 2,
+// o ile obraz ma też piksele nieprzezroczyste.
+func Palette(img image.Image, n int, method Method) (color.Palette, error) {
+	if n < 1 || n > 256 {
+		return nil, fmt.Errorf("palette size must be in 1..256, got %d", n)
+	}
+	colors, transparent := collectColors(img)
+	if transparent {
+		if n < 2 && len(colors) > 0 {
+			return nil, fmt.Errorf("palette size must be at least 2 for an image with transparent pixels, got %d", n)
+		}
+		n--
+	}
+
+	var centers [][3]int
+	switch method {
+	case MedianCut:
+		centers = medianCut(colors, n)
+	case Octree:
+		centers = octree(colors, n)
+	case KMeans:
+		centers = kmeans(colors, medianCut(colors, n))
+	default:
+		return nil, fmt.Errorf("unknown quantization method %q", method)
+	}
+
+	palette := color.Palette{}
+	for _, c := range centers {
+		palette = append(palette, color.NRGBA{R: uint8(c[0]), G: uint8(c[1]), B: uint8(c[2]), A: 255})
+	}
+	if transparent || len(palette) == 0 {
+		palette = append(palette, Transparent)
+	}
+	return palette, nil
+}
+
+// Quantize zmniejsza liczbę kolorów obrazu do n; dither włącza rozpraszanie błędu Floyda-Steinberga
+func Quantize(img image.Image, n int, method Method, dither bool) (*image.Paletted, error) {
+	palette, err := Palette(img, n, method)
+	if err != nil {
+		return nil, err
+	}
+	return MapToPalette(img, palette, dither), nil
+}
+
+// MapToPalette zamienia każdy piksel na najbliższy (w RGB) kolor podanej palety.
+// Z ditheringiem i bez obowiązuje ta sama reguła alfy: piksele o alfie < 128 dostają
+// przezroczysty kolor palety (jeśli jest), pozostałe - jeden z kolorów nieprzezroczystych.
+func MapToPalette(img image.Image, palette color.Palette, dither bool) *image.Paletted {
+	bounds := img.Bounds()
+	out := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+
+	transparent := -1
+	opaque := make([][3]int, len(palette))
+	for i, c := range palette {
+		nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+		opaque[i] = [3]int{int(nc.R), int(nc.G), int(nc.B)}
+		if nc.A < 128 && transparent < 0 {
+			transparent = i
+		}
+	}
+	if dither {
+		// Dyfuzja na obrazie z alfą sprowadzoną do 0 lub 255, potem poprawka pikseli,
+		// które trafiły po złej stronie progu alfy
+		src := binaryAlpha(img, transparent >= 0)
+		draw.FloydSteinberg.Draw(out, out.Rect, src, image.Point{})
+		if transparent < 0 {
+			return out
+		}
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				c := src.NRGBAAt(x, y)
+				idx := int(out.ColorIndexAt(x, y))
+				if c.A == 0 {
+					out.SetColorIndex(x, y, uint8(transparent))
+				} else if idx == transparent {
+					out.SetColorIndex(x, y, uint8(nearest(opaque, [3]int{int(c.R), int(c.G), int(c.B)}, transparent)))
+				}
+			}
+		}
+		return out
+	}
+
+	cache := map[uint32]uint8{}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			if c.A < 128 && transparent >= 0 {
+				out.SetColorIndex(x, y, uint8(transparent))
+				continue
+			}
+			key := uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+			idx, ok := cache[key]
+			if !ok {
+				idx = uint8(nearest(opaque, [3]int{int(c.R), int(c.G), int(c.B)}, transparent))
+				cache[key] = idx
+			}
+			out.SetColorIndex(x, y, idx)
+		}
+	}
+	return out
+}
+
+// binaryAlpha kopiuje obraz z pełnym kryciem; gdy keepTransparent jest ustawione,
+// piksele o alfie < 128 zamieniane są na Transparent
+func binaryAlpha(img image.Image, keepTransparent bool) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			if keepTransparent && c.A < 128 {
+				c = Transparent
+			} else {
+				c.A = 255
+			}
+			out.SetNRGBA(x, y, c)
+		}
+	}
+	return out
+}
+
+// ParsePalette odczytuje paletę z listy kolorów szesnastkowych rozdzielonych przecinkami, np. "#000000,#ff8800"
+func ParsePalette(list string) (color.Palette, error) {
+	palette := color.Palette{}
+	for _, item := range strings.Split(list, ",") {
+		hex := strings.TrimPrefix(strings.TrimSpace(item), "#")
+		if hex == "" {
+			continue
+		}
+		if len(hex) != 6 {
+			return nil, fmt.Errorf("invalid color %q, expected #rrggbb", item)
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q, expected #rrggbb", item)
+		}
+		palette = append(palette, color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255})
+	}
+	if len(palette) == 0 || len(palette) > 256 {
+		return nil, fmt.Errorf("palette must have 1..256 colors, got %d", len(palette))
+	}
+	return palette, nil
+}
+
+// collectColors zlicza różne kolory nieprzezroczystych pikseli i sprawdza, czy są piksele przezroczyste
+func collectColors(img image.Image) ([]weightedColor, bool) {
+	counts := map[uint32]int{}
+	transparent := false
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				transparent = true
+				continue
+			}
+			counts[uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B)]++
+		}
+	}
+	colors := make([]weightedColor, 0, len(counts))
+	for key, count := range counts {
+		colors = append(colors, weightedColor{rgb: [3]int{int(key >> 16), int(key >> 8 & 0xff), int(key & 0xff)}, count: count})
+	}
+	// Stała kolejność, żeby wynik nie zależał od kolejności iteracji po mapie
+	sort.Slice(colors, func(i, j int) bool { return packRGB(colors[i].rgb) < packRGB(colors[j].rgb) })
+	return colors, transparent
+}
+
+// nearest - indeks najbliższego koloru palety z pominięciem indeksu skip
+func nearest(palette [][3]int, c [3]int, skip int) int {
+	best, bestDist := 0, -1
+	for i, p := range palette {
+		if i == skip {
+			continue
+		}
+		dr, dg, db := p[0]-c[0], p[1]-c[1], p[2]-c[2]
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// mean - średni kolor ważony liczbą pikseli
+func mean(colors []weightedColor) [3]int {
+	var sum [3]int
+	total := 0
+	for _, c := range colors {
+		for k := range sum {
+			sum[k] += c.rgb[k] * c.count
+		}
+		total += c.count
+	}
+	if total == 0 {
+		return [3]int{}
+	}
+	for k := range sum {
+		sum[k] = (sum[k] + total/2) / total
+	}
+	return sum
+}
+
+func packRGB(c [3]int) int {
+	return c[0]<<16 | c[1]<<8 | c[2]
+}
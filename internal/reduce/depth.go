@@ -0,0 +1,117 @@
+package reduce
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "math"
+)
+
+// Depth - liczba bitów dla każdego kanału (1..8; 8 oznacza kanał bez zmian)
+type Depth struct {
+    R, G, B, A uint8
+}
+
+// Popularne formaty o mniejszej głębi kolorów
+var (
+    RGB888   = Depth{R: 8, G: 8, B: 8, A: 8}
+    RGB565   = Depth{R: 5, G: 6, B: 5, A: 8}
+    RGB555   = Depth{R: 5, G: 5, B: 5, A: 8}
+    RGB444   = Depth{R: 4, G: 4, B: 4, A: 8}
+    RGB332   = Depth{R: 3, G: 3, B: 2, A: 8}
+    RGBA4444 = Depth{R: 4, G: 4, B: 4, A: 4}
+    RGBA5551 = Depth{R: 5, G: 5, B: 5, A: 1}
+)
+
+// Depths - formaty dostępne po nazwie
+var Depths = map[string]Depth{
+    "rgb888":   RGB888,
+    "rgb565":   RGB565,
+    "rgb555":   RGB555,
+    "rgb444":   RGB444,
+    "rgb332":   RGB332,
+    "rgba4444": RGBA4444,
+    "rgba5551": RGBA5551,
+}
+
+// PosterizeValue zaokrągla wartość do najbliższego z levels poziomów rozłożonych równo na 0..255
+// (np. dla 4 poziomów: 0, 85, 170, 255). levels spoza 2..256 jest przycinane do tego zakresu.
+func PosterizeValue(v uint8, levels int) uint8 {
+    levels = max(2, min(256, levels))
+    n := float64(levels - 1)
+    return uint8(math.Round(math.Round(float64(v)*n/255) * 255 / n))
+}
+
+// RoundBits zmniejsza wartość do bits bitów z zaokrągleniem do najbliższego poziomu.
+// W przeciwieństwie do maskowania w ReduceBits poziomy obejmują cały zakres, np. 4 bity to 0, 17, ..., 255.
+// bits == 0 jest traktowane jak 1 bit, bits > 8 jak 8.
+func RoundBits(v uint8, bits uint8) uint8 {
+    if bits >= 8 {
+        return v
+    }
+    bits = max(bits, 1)
+    return PosterizeValue(v, 1<<bits)
+}
+
+// ReduceBitsRounded - odpowiednik ReduceBits z zaokrąglaniem zamiast obcinania
+func ReduceBitsRounded(r, g, b uint8, bits uint8) (uint8, uint8, uint8) {
+    return RoundBits(r, bits), RoundBits(g, bits), RoundBits(b, bits)
+}
+
+// ApplyDepth zmniejsza głębię każdego kanału (także alfy) do podanej liczby bitów z zaokrągleniem.
+// Kanały są zaokrąglane bez premultiplikacji; przy 1 bicie alfy piksele o alfie >= 128 stają się
+// nieprzezroczyste, a pozostałe w pełni przezroczyste.
+func ApplyDepth(img image.Image, depth Depth) (*image.RGBA, error) {
+    if err := checkDepth(depth); err != nil {
+        return nil, err
+    }
+    return mapNRGBA(img, func(c color.NRGBA) color.NRGBA {
+        return color.NRGBA{R: RoundBits(c.R, depth.R), G: RoundBits(c.G, depth.G), B: RoundBits(c.B, depth.B), A: RoundBits(c.A, depth.A)}
+    }), nil
+}
+
+// Posterize ogranicza każdy kanał R, G, B do levels poziomów (2..256) rozłożonych na pełny zakres.
+// Gdy posterizeAlpha jest ustawione, to samo dotyczy kanału alfa; inaczej alfa zostaje bez zmian.
+func Posterize(img image.Image, levels int, posterizeAlpha bool) (*image.RGBA, error) {
+    if levels < 2 || levels > 256 {
+        return nil, fmt.Errorf("posterize levels must be in 2..256, got %d", levels)
+    }
+    return mapNRGBA(img, func(c color.NRGBA) color.NRGBA {
+        out := color.NRGBA{R: PosterizeValue(c.R, levels), G: PosterizeValue(c.G, levels), B: PosterizeValue(c.B, levels), A: c.A}
+        if posterizeAlpha {
+            out.A = PosterizeValue(c.A, levels)
+        }
+        return out
+    }), nil
+}
+
+func checkDepth(depth Depth) error {
+    for _, bits := range []uint8{depth.R, depth.G, depth.B, depth.A} {
+        if bits < 1 || bits > 8 {
+            return fmt.Errorf("channel depth must be in 1..8 bits, got %d", bits)
+        }
+    }
+    return nil
+}
+
+// depthLevels - poziomy kanału o danej liczbie bitów, rozłożone na pełny zakres 0..255
+func depthLevels(bits uint8) []float64 {
+    n := 1 << bits
+    levels := make([]float64, n)
+    for i := range levels {
+        levels[i] = math.Round(float64(i) * 255 / float64(n-1))
+    }
+    return levels
+}
+
+func mapNRGBA(img image.Image, f func(c color.NRGBA) color.NRGBA) *image.RGBA {
+    bounds := img.Bounds()
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            out.Set(x, y, f(c))
+        }
+    }
+    return out
+}
@@ -0,0 +1,81 @@
+package reduce
+
+import (
+    "fmt"
+    "image"
+    "image-processing/v1/internal/dither"
+    "image/color"
+    "math"
+)
+
+// ApplyDitheredBitReduction zmniejsza liczbę bitów na kanał R, G, B do poziomów ReduceBitsRounded
+// (rozłożonych na pełny zakres 0..255), rozpraszając błąd kwantyzacji wybraną metodą,
+// co usuwa pasmowanie. Kanał alfa zostaje zachowany.
+func ApplyDitheredBitReduction(img image.Image, bits uint8, opts dither.Options) (*image.RGBA, error) {
+    if bits < 1 || bits > 8 {
+        return nil, fmt.Errorf("bits per channel must be in 1..8, got %d", bits)
+    }
+    levels := depthLevels(bits)
+    return ditherLevels(img, [3][]float64{levels, levels, levels}, nil, opts)
+}
+
+// ApplyDitheredDepth - ApplyDepth z rozpraszaniem błędu w kanałach R, G, B; alfa jest tylko zaokrąglana
+func ApplyDitheredDepth(img image.Image, depth Depth, opts dither.Options) (*image.RGBA, error) {
+    if err := checkDepth(depth); err != nil {
+        return nil, err
+    }
+    levels := [3][]float64{depthLevels(depth.R), depthLevels(depth.G), depthLevels(depth.B)}
+    return ditherLevels(img, levels, depthLevels(depth.A), opts)
+}
+
+// ditherLevels kwantyzuje kanały R, G, B do podanych poziomów z ditheringiem.
+// alphaLevels == nil pozostawia alfę bez zmian.
+func ditherLevels(img image.Image, levels [3][]float64, alphaLevels []float64, opts dither.Options) (*image.RGBA, error) {
+    bounds := img.Bounds()
+    planes := [3][][]float64{}
+    alpha := make([][]uint8, bounds.Dy())
+    for c := range planes {
+        planes[c] = make([][]float64, bounds.Dy())
+    }
+    for y := 0; y < bounds.Dy(); y++ {
+        alpha[y] = make([]uint8, bounds.Dx())
+        for c := range planes {
+            planes[c][y] = make([]float64, bounds.Dx())
+        }
+        for x := 0; x < bounds.Dx(); x++ {
+            c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+            planes[0][y][x], planes[1][y][x], planes[2][y][x] = float64(c.R), float64(c.G), float64(c.B)
+            alpha[y][x] = c.A
+        }
+    }
+
+    for c := range planes {
+        step := 255.0
+        if len(levels[c]) > 1 {
+            step = levels[c][1] - levels[c][0]
+        }
+        var err error
+        planes[c], err = dither.Apply(planes[c], dither.NearestLevel(levels[c]), step, opts)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    quantizeAlpha := func(a uint8) uint8 { return a }
+    if alphaLevels != nil {
+        nearest := dither.NearestLevel(alphaLevels)
+        quantizeAlpha = func(a uint8) uint8 { return uint8(nearest(float64(a))) }
+    }
+    out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    for y := 0; y < bounds.Dy(); y++ {
+        for x := 0; x < bounds.Dx(); x++ {
+            out.Set(x, y, color.NRGBA{
+                R: uint8(math.Round(planes[0][y][x])),
+                G: uint8(math.Round(planes[1][y][x])),
+                B: uint8(math.Round(planes[2][y][x])),
+                A: quantizeAlpha(alpha[y][x]),
+            })
+        }
+    }
+    return out, nil
+}
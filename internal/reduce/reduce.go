@@ -1,3 +1,5 @@
+// Package reduce zmniejsza liczbę bitów na kanał, opcjonalnie z ditheringiem (pakiet dither).
+// Alfa: redukowany jest kolor bez premultiplikacji, kanał alfa zostaje zachowany bez zmian.
 package reduce
 
 import (
@@ -5,6 +7,7 @@ import (
 	"image/color"
 )
 
+// ReduceBits obcina młodsze bity maską, co przyciemnia obraz; zaokrąglanie daje ReduceBitsRounded
 func ReduceBits(r, g, b uint8, bits uint8) (uint8, uint8, uint8) {
     if bits > 8 {
         bits = 8 // Maksymalna liczba bitów to 8
@@ -29,9 +32,9 @@ func ApplyBitReductionToImage(img image.Image, bits uint8) *image.RGBA {
 
     for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
         for x := bounds.Min.X; x < bounds.Max.X; x++ {
-            r, g, b, a := img.At(x, y).RGBA()
-            reducedR, reducedG, reducedB := ReduceBits(uint8(r>>8), uint8(g>>8), uint8(b>>8), bits)
-            reducedImg.Set(x, y, color.RGBA{R: reducedR, G: reducedG, B: reducedB, A: uint8(a >> 8)})
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            reducedR, reducedG, reducedB := ReduceBits(c.R, c.G, c.B, bits)
+            reducedImg.Set(x, y, color.NRGBA{R: reducedR, G: reducedG, B: reducedB, A: c.A})
         }
     }
     return reducedImg
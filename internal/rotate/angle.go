@@ -0,0 +1,86 @@
+package rotate
+
+import (
+    "image"
+    "image/color"
+    "image-processing/v1/internal/interpolate"
+    "math"
+)
+
+type Canvas string
+
+const (
+    Expand Canvas = "expand" // płótno powiększone tak, by zmieścić cały obrócony obraz
+    Crop   Canvas = "crop"   // płótno o rozmiarze oryginału
+)
+
+// Options - ustawienia obrotu o dowolny kąt.
+// Wartości zerowe: interpolacja dwuliniowa, płótno powiększone, przezroczyste tło.
+type Options struct {
+    Interpolation interpolate.Method
+    Canvas        Canvas
+    Background    color.Color
+}
+
+// RotateAngle obraca obraz o dowolny kąt w stopniach wokół jego środka.
+// Dodatni kąt oznacza obrót zgodny z ruchem wskazówek zegara, tak jak w RotateImage.
+func RotateAngle(img image.Image, degrees float64, opts Options) *image.RGBA {
+    bounds := img.Bounds()
+    cx := float64(bounds.Min.X+bounds.Max.X) / 2
+    cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+    return RotateAround(img, degrees, cx, cy, opts)
+}
+
+// RotateAround obraca obraz o dowolny kąt wokół punktu (cx, cy) podanego we współrzędnych obrazu
+func RotateAround(img image.Image, degrees, cx, cy float64, opts Options) *image.RGBA {
+    bounds := img.Bounds()
+    rad := degrees * math.Pi / 180
+    sin, cos := math.Sincos(rad)
+    // Dokładne wartości dla wielokrotności 90 stopni
+    sin, cos = snap(sin), snap(cos)
+
+    // Obszar docelowy we współrzędnych obróconych
+    minX, minY := float64(bounds.Min.X), float64(bounds.Min.Y)
+    maxX, maxY := float64(bounds.Max.X), float64(bounds.Max.Y)
+    if opts.Canvas != Crop {
+        corners := [4][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}}
+        minX, minY = math.Inf(1), math.Inf(1)
+        maxX, maxY = math.Inf(-1), math.Inf(-1)
+        for _, c := range corners {
+            dx, dy := c[0]-cx, c[1]-cy
+            x := cx + dx*cos - dy*sin
+            y := cy + dx*sin + dy*cos
+            minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+            minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+        }
+    }
+    width := int(math.Ceil(maxX - minX - 1e-6))
+    height := int(math.Ceil(maxY - minY - 1e-6))
+
+    method := opts.Interpolation
+    if method == "" {
+        method = interpolate.Bilinear
+    }
+
+    rotatedImg := image.NewRGBA(image.Rect(0, 0, width, height))
+    for y := 0; y < height; y++ {
+        for x := 0; x < width; x++ {
+            // Odwzorowanie odwrotne: środek piksela docelowego -> punkt w obrazie źródłowym
+            dx := minX + float64(x) + 0.5 - cx
+            dy := minY + float64(y) + 0.5 - cy
+            sx := cx + dx*cos + dy*sin
+            sy := cy - dx*sin + dy*cos
+            rotatedImg.SetRGBA64(x, y, interpolate.At(img, sx, sy, method, opts.Background))
+        }
+    }
+    return rotatedImg
+}
+
+// snap zaokrągla wartości bliskie -1, 0 i 1
+func snap(v float64) float64 {
+    r := math.Round(v)
+    if math.Abs(v-r) < 1e-12 {
+        return r
+    }
+    return v
+}
@@ -1,3 +1,6 @@
+// Package rotate obraca obraz o wielokrotność 90 stopni lub o dowolny kąt.
+// Alfa: obroty o 90 stopni kopiują piksele bez zmian; przy dowolnym kącie interpolacja działa
+// na wartościach premultiplikowanych, a tło domyślnie jest przezroczyste.
 package rotate
 
 import (
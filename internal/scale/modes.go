@@ -0,0 +1,109 @@
+package scale
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "image/draw"
+    "math"
+)
+
+// fitSize liczy największy rozmiar mieszczący się w ramce maxW x maxH z zachowaniem proporcji
+func fitSize(srcW, srcH, maxW, maxH int) (int, int) {
+    ratio := math.Min(float64(maxW)/float64(srcW), float64(maxH)/float64(srcH))
+    w := int(math.Round(float64(srcW) * ratio))
+    h := int(math.Round(float64(srcH) * ratio))
+    return max(w, 1), max(h, 1)
+}
+
+// Fit zmniejsza lub powiększa obraz tak, by zmieścił się w ramce maxW x maxH bez zmiany proporcji
+func Fit(img image.Image, maxW, maxH int, filter Filter) (*image.RGBA, error) {
+    if err := checkSize(img, maxW, maxH); err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    w, h := fitSize(bounds.Dx(), bounds.Dy(), maxW, maxH)
+    return ResizeImageFilter(img, w, h, filter)
+}
+
+// Fill skaluje obraz tak, by pokrył całą ramkę w x h, a nadmiar przycina centralnie
+func Fill(img image.Image, w, h int, filter Filter) (*image.RGBA, error) {
+    if err := checkSize(img, w, h); err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    ratio := math.Max(float64(w)/float64(bounds.Dx()), float64(h)/float64(bounds.Dy()))
+    scaledW := max(int(math.Ceil(float64(bounds.Dx())*ratio-1e-9)), w)
+    scaledH := max(int(math.Ceil(float64(bounds.Dy())*ratio-1e-9)), h)
+    scaled, err := ResizeImageFilter(img, scaledW, scaledH, filter)
+    if err != nil {
+        return nil, err
+    }
+
+    out := image.NewRGBA(image.Rect(0, 0, w, h))
+    offset := image.Pt((scaledW-w)/2, (scaledH-h)/2)
+    draw.Draw(out, out.Bounds(), scaled, offset, draw.Src)
+    return out, nil
+}
+
+// Pad dopasowuje obraz do ramki w x h (jak Fit) i wyśrodkowuje go na tle bg (nil = przezroczyste)
+func Pad(img image.Image, w, h int, bg color.Color, filter Filter) (*image.RGBA, error) {
+    fitted, err := Fit(img, w, h, filter)
+    if err != nil {
+        return nil, err
+    }
+    out := image.NewRGBA(image.Rect(0, 0, w, h))
+    if bg != nil {
+        draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+    }
+    fb := fitted.Bounds()
+    offset := image.Pt((w-fb.Dx())/2, (h-fb.Dy())/2)
+    draw.Draw(out, fb.Add(offset), fitted, image.Point{}, draw.Over)
+    return out, nil
+}
+
+// ScalePercent skaluje obraz o podany procent (100 = bez zmian)
+func ScalePercent(img image.Image, percent float64, filter Filter) (*image.RGBA, error) {
+    if percent <= 0 || math.IsInf(percent, 0) || math.IsNaN(percent) {
+        return nil, fmt.Errorf("invalid scale percent %v: must be positive", percent)
+    }
+    bounds := img.Bounds()
+    w := max(int(math.Round(float64(bounds.Dx())*percent/100)), 1)
+    h := max(int(math.Round(float64(bounds.Dy())*percent/100)), 1)
+    return ResizeImageFilter(img, w, h, filter)
+}
+
+// ResizeWidth zmienia szerokość, a wysokość dobiera proporcjonalnie
+func ResizeWidth(img image.Image, w int, filter Filter) (*image.RGBA, error) {
+    if err := checkSize(img, w, 1); err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    h := max(int(math.Round(float64(bounds.Dy())*float64(w)/float64(bounds.Dx()))), 1)
+    return ResizeImageFilter(img, w, h, filter)
+}
+
+// ResizeHeight zmienia wysokość, a szerokość dobiera proporcjonalnie
+func ResizeHeight(img image.Image, h int, filter Filter) (*image.RGBA, error) {
+    if err := checkSize(img, 1, h); err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    w := max(int(math.Round(float64(bounds.Dx())*float64(h)/float64(bounds.Dy()))), 1)
+    return ResizeImageFilter(img, w, h, filter)
+}
+
+// Thumbnail działa jak Fit, ale nigdy nie powiększa obrazu mniejszego od ramki
+func Thumbnail(img image.Image, maxW, maxH int, filter Filter) (*image.RGBA, error) {
+    if err := checkSize(img, maxW, maxH); err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    if bounds.Dx() <= maxW && bounds.Dy() <= maxH {
+        // Obraz już się mieści - zwykła kopia, bez ponownego próbkowania
+        out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+        draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+        return out, nil
+    }
+    return Fit(img, maxW, maxH, filter)
+}
@@ -1,3 +1,6 @@
+// Package scale zmienia rozmiar obrazu.
+// Alfa: wszystkie metody interpolują wartości premultiplikowane i zapisują wynik jako image.RGBA
+// (również premultiplikowany), więc przezroczyste piksele nie tworzą ciemnych obwódek.
 package scale
 
 import (
@@ -104,11 +107,13 @@ func ResizeImageBilinear(src image.Image, newW, newH int) image.Image {
                 wx, wy,
             )
 
-            dst.Set(x, y, color.NRGBA{
-                R: uint8(clamp(int(r+0.5), 0, 255)),
-                G: uint8(clamp(int(g+0.5), 0, 255)),
-                B: uint8(clamp(int(b+0.5), 0, 255)),
-                A: uint8(clamp(int(a+0.5), 0, 255)),
+            // Wartości z RGBA() są premultiplikowane, więc wynik też musi być premultiplikowany
+            alpha := clamp(int(a+0.5), 0, 255)
+            dst.SetRGBA(x, y, color.RGBA{
+                R: uint8(clamp(int(r+0.5), 0, alpha)),
+                G: uint8(clamp(int(g+0.5), 0, alpha)),
+                B: uint8(clamp(int(b+0.5), 0, alpha)),
+                A: uint8(alpha),
             })
         }
     }
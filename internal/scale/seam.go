@@ -0,0 +1,282 @@
+package scale
+
+import (
+    "fmt"
+    "image"
+    "image-processing/v1/internal/convolution"
+    "image/color"
+    "math"
+)
+
+// Energia dodawana (lub odejmowana) pikselom z maski, by szwy omijały je lub przez nie przechodziły
+const maskEnergy = 1e6
+
+// SeamCarve zmienia rozmiar obrazu z zachowaniem treści (seam carving): usuwa lub wstawia szwy
+// o najmniejszej energii (moduł gradientu Sobela). Najpierw zmieniana jest szerokość, potem wysokość.
+// mask (może być nil) ma rozmiar obrazu: wartości > 0 chronią piksele, < 0 wskazują piksele do usunięcia.
+func SeamCarve(img image.Image, newW, newH int, mask [][]int) (*image.RGBA, error) {
+    if err := checkSize(img, newW, newH); err != nil {
+        return nil, err
+    }
+    if err := checkMask(img, mask); err != nil {
+        return nil, err
+    }
+    pix := imageToRows(img)
+    m := maskOrZero(mask, len(pix), len(pix[0]))
+    pix, m = carveWidth(pix, m, newW)
+    pixT, mT := transpose(pix), transpose(m)
+    pixT, _ = carveWidth(pixT, mT, newH)
+    return rowsToImage(transpose(pixT)), nil
+}
+
+// SeamCarveRemove usuwa pionowe szwy, dopóki w obrazie zostają piksele oznaczone w masce jako do usunięcia (< 0).
+// Gdy restore jest ustawione, obraz jest następnie poszerzany szwami do pierwotnej szerokości.
+func SeamCarveRemove(img image.Image, mask [][]int, restore bool) (*image.RGBA, error) {
+    if img.Bounds().Empty() {
+        return nil, fmt.Errorf("cannot carve an empty image")
+    }
+    if err := checkMask(img, mask); err != nil {
+        return nil, err
+    }
+    pix := imageToRows(img)
+    width := len(pix[0])
+    m := maskOrZero(mask, len(pix), width)
+    for hasRemoval(m) && len(pix[0]) > 1 {
+        seam := findSeam(energy(pix, m))
+        pix = removeSeam(pix, seam)
+        m = removeSeam(m, seam)
+    }
+    if restore {
+        pix, _ = carveWidth(pix, m, width)
+    }
+    return rowsToImage(pix), nil
+}
+
+// checkMask - maska (jeśli podana) musi mieć dokładnie rozmiar obrazu
+func checkMask(img image.Image, mask [][]int) error {
+    if mask == nil {
+        return nil
+    }
+    size := img.Bounds().Size()
+    if len(mask) != size.Y {
+        return fmt.Errorf("mask has %d rows, image is %d pixels tall", len(mask), size.Y)
+    }
+    for y, row := range mask {
+        if len(row) != size.X {
+            return fmt.Errorf("mask row %d has %d values, image is %d pixels wide", y, len(row), size.X)
+        }
+    }
+    return nil
+}
+
+// carveWidth usuwa lub wstawia pionowe szwy, aż obraz osiągnie szerokość w
+func carveWidth(pix [][]color.RGBA64, m [][]int, w int) ([][]color.RGBA64, [][]int) {
+    for len(pix[0]) > w && len(pix[0]) > 1 {
+        seam := findSeam(energy(pix, m))
+        pix = removeSeam(pix, seam)
+        m = removeSeam(m, seam)
+    }
+    for len(pix[0]) < w {
+        // Naraz wstawiamy co najwyżej połowę bieżącej szerokości, by nie powielać tych samych szwów
+        k := min(w-len(pix[0]), max(len(pix[0])/2, 1))
+        pix, m = insertSeams(pix, m, k)
+    }
+    return pix, m
+}
+
+// insertSeams znajduje k szwów do usunięcia na kopii obrazu i powiela je w oryginale.
+// Obraz o szerokości 1 też można poszerzyć - jedyna kolumna jest wtedy powielana.
+func insertSeams(pix [][]color.RGBA64, m [][]int, k int) ([][]color.RGBA64, [][]int) {
+    h, w := len(pix), len(pix[0])
+    // index zapamiętuje pierwotną kolumnę każdego piksela kopii
+    index := make([][]int, h)
+    for y := range index {
+        index[y] = make([]int, w)
+        for x := range index[y] {
+            index[y][x] = x
+        }
+    }
+    work, workMask := pix, m
+    duplicate := make([][]bool, h)
+    for y := range duplicate {
+        duplicate[y] = make([]bool, w)
+    }
+    for i := 0; i < k && len(work[0]) > 0; i++ {
+        seam := findSeam(energy(work, workMask))
+        for y, x := range seam {
+            duplicate[y][index[y][x]] = true
+        }
+        work = removeSeam(work, seam)
+        workMask = removeSeam(workMask, seam)
+        index = removeSeam(index, seam)
+    }
+
+    outPix := make([][]color.RGBA64, h)
+    outMask := make([][]int, h)
+    for y := 0; y < h; y++ {
+        outPix[y] = make([]color.RGBA64, 0, w+k)
+        outMask[y] = make([]int, 0, w+k)
+        for x := 0; x < w; x++ {
+            outPix[y] = append(outPix[y], pix[y][x])
+            outMask[y] = append(outMask[y], m[y][x])
+            if duplicate[y][x] {
+                next := pix[y][min(x+1, w-1)]
+                outPix[y] = append(outPix[y], average(pix[y][x], next))
+                outMask[y] = append(outMask[y], m[y][x])
+            }
+        }
+    }
+    return outPix, outMask
+}
+
+// energy - moduł gradientu Sobela jasności (konwolucja z pakietu convolution) plus wpływ maski
+func energy(pix [][]color.RGBA64, m [][]int) [][]float64 {
+    h, w := len(pix), len(pix[0])
+    gray := make([][]float64, h)
+    for y := 0; y < h; y++ {
+        gray[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            c := pix[y][x]
+            gray[y][x] = (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 257
+        }
+    }
+    gx := convolution.Convolve(gray, [][]float64{
+        {-1, 0, 1},
+        {-2, 0, 2},
+        {-1, 0, 1},
+    }, convolution.Replicate)
+    gy := convolution.Convolve(gray, [][]float64{
+        {-1, -2, -1},
+        {0, 0, 0},
+        {1, 2, 1},
+    }, convolution.Replicate)
+    out := make([][]float64, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            out[y][x] = math.Hypot(gx[y][x], gy[y][x])
+            if m[y][x] > 0 {
+                out[y][x] += maskEnergy
+            } else if m[y][x] < 0 {
+                out[y][x] -= maskEnergy
+            }
+        }
+    }
+    return out
+}
+
+// findSeam - programowanie dynamiczne: pionowy szew o najmniejszej sumie energii (kolumna w każdym wierszu)
+func findSeam(e [][]float64) []int {
+    h, w := len(e), len(e[0])
+    cost := make([][]float64, h)
+    cost[0] = append([]float64(nil), e[0]...)
+    for y := 1; y < h; y++ {
+        cost[y] = make([]float64, w)
+        for x := 0; x < w; x++ {
+            best := cost[y-1][x]
+            if x > 0 {
+                best = math.Min(best, cost[y-1][x-1])
+            }
+            if x < w-1 {
+                best = math.Min(best, cost[y-1][x+1])
+            }
+            cost[y][x] = e[y][x] + best
+        }
+    }
+    seam := make([]int, h)
+    for x := 1; x < w; x++ {
+        if cost[h-1][x] < cost[h-1][seam[h-1]] {
+            seam[h-1] = x
+        }
+    }
+    for y := h - 2; y >= 0; y-- {
+        prev := seam[y+1]
+        seam[y] = prev
+        for _, x := range []int{prev - 1, prev + 1} {
+            if x >= 0 && x < w && cost[y][x] < cost[y][seam[y]] {
+                seam[y] = x
+            }
+        }
+    }
+    return seam
+}
+
+// removeSeam usuwa z każdego wiersza kolumnę wskazaną przez szew
+func removeSeam[T any](rows [][]T, seam []int) [][]T {
+    out := make([][]T, len(rows))
+    for y, row := range rows {
+        x := seam[y]
+        out[y] = make([]T, 0, len(row)-1)
+        out[y] = append(out[y], row[:x]...)
+        out[y] = append(out[y], row[x+1:]...)
+    }
+    return out
+}
+
+// transpose zamienia wiersze z kolumnami
+func transpose[T any](rows [][]T) [][]T {
+    h, w := len(rows), len(rows[0])
+    out := make([][]T, w)
+    for x := 0; x < w; x++ {
+        out[x] = make([]T, h)
+        for y := 0; y < h; y++ {
+            out[x][y] = rows[y][x]
+        }
+    }
+    return out
+}
+
+func hasRemoval(m [][]int) bool {
+    for _, row := range m {
+        for _, v := range row {
+            if v < 0 {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// maskOrZero kopiuje maskę (sprawdzoną przez checkMask) lub tworzy pustą, gdy mask == nil
+func maskOrZero(mask [][]int, h, w int) [][]int {
+    out := make([][]int, h)
+    for y := 0; y < h; y++ {
+        out[y] = make([]int, w)
+        if mask != nil {
+            copy(out[y], mask[y])
+        }
+    }
+    return out
+}
+
+func average(a, b color.RGBA64) color.RGBA64 {
+    return color.RGBA64{
+        R: uint16((uint32(a.R) + uint32(b.R) + 1) / 2),
+        G: uint16((uint32(a.G) + uint32(b.G) + 1) / 2),
+        B: uint16((uint32(a.B) + uint32(b.B) + 1) / 2),
+        A: uint16((uint32(a.A) + uint32(b.A) + 1) / 2),
+    }
+}
+
+// imageToRows kopiuje obraz do macierzy kolorów premultiplikowanych
+func imageToRows(img image.Image) [][]color.RGBA64 {
+    bounds := img.Bounds()
+    out := make([][]color.RGBA64, bounds.Dy())
+    for y := range out {
+        out[y] = make([]color.RGBA64, bounds.Dx())
+        for x := range out[y] {
+            out[y][x] = color.RGBA64Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.RGBA64)
+        }
+    }
+    return out
+}
+
+func rowsToImage(rows [][]color.RGBA64) *image.RGBA {
+    out := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+    for y, row := range rows {
+        for x, c := range row {
+            out.SetRGBA64(x, y, c)
+        }
+    }
+    return out
+}
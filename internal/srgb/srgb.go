@@ -0,0 +1,51 @@
+// Package srgb przelicza wartości między kodowaniem sRGB a światłem liniowym.
+package srgb
+
+import "math"
+
+// Tablice przejścia między sRGB a światłem liniowym
+var (
+	toLinear   [256]float64
+	fromLinear [65536]uint8
+)
+
+func init() {
+	for i := range toLinear {
+		toLinear[i] = Decode(float64(i) / 255)
+	}
+	for i := range fromLinear {
+		fromLinear[i] = uint8(math.Round(Encode(float64(i)/65535) * 255))
+	}
+}
+
+// Decode zamienia wartość sRGB (0..1) na światło liniowe (0..1)
+func Decode(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// Encode zamienia światło liniowe (0..1) na wartość sRGB (0..1)
+func Encode(l float64) float64 {
+	if l <= 0.0031308 {
+		return l * 12.92
+	}
+	return 1.055*math.Pow(l, 1/2.4) - 0.055
+}
+
+// ToLinear zamienia 8-bitową wartość sRGB na światło liniowe (0..1) przy użyciu tablicy
+func ToLinear(v uint8) float64 {
+	return toLinear[v]
+}
+
+// FromLinear zamienia światło liniowe (0..1, wartości spoza zakresu są przycinane) na 8-bitowe sRGB
+func FromLinear(l float64) uint8 {
+	if l <= 0 {
+		return 0
+	}
+	if l >= 1 {
+		return 255
+	}
+	return fromLinear[int(l*65535+0.5)]
+}
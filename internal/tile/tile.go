@@ -0,0 +1,145 @@
+// Package tile dzieli obraz na kafelki i składa wiele obrazów w arkusz stykowy.
+// Alfa: kafelki zachowują alfę; miniatury na arkuszu są nakładane na tło operacją "over".
+package tile
+
+import (
+	"fmt"
+	"image"
+	"image-processing/v1/internal/canvas"
+	"image-processing/v1/internal/grayscale"
+	"image-processing/v1/internal/scale"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Tile - fragment obrazu razem z jego położeniem w obrazie źródłowym
+type Tile struct {
+	Rect  image.Rectangle
+	Image *image.RGBA
+}
+
+// Wysokość paska z podpisem pod miniaturą
+const labelHeight = 16
+
+// Split dzieli obraz na siatkę cols x rows. Każdy kafelek jest poszerzany o overlap pikseli
+// w każdą stronę (w granicach obrazu), co ułatwia późniejsze sklejanie przetworzonych kafelków.
+// Kafelki są zwracane wierszami, od lewego górnego.
+func Split(img image.Image, cols, rows, overlap int) ([]Tile, error) {
+	bounds := img.Bounds()
+	if cols <= 0 || rows <= 0 {
+		return nil, fmt.Errorf("invalid grid %dx%d: cols and rows must be positive", cols, rows)
+	}
+	if cols > bounds.Dx() || rows > bounds.Dy() {
+		return nil, fmt.Errorf("grid %dx%d is larger than the %dx%d image", cols, rows, bounds.Dx(), bounds.Dy())
+	}
+	if err := checkOverlap(overlap, bounds.Dx()/cols, bounds.Dy()/rows); err != nil {
+		return nil, err
+	}
+	tiles := make([]Tile, 0, cols*rows)
+	for r := 0; r < rows; r++ {
+		y0 := bounds.Min.Y + bounds.Dy()*r/rows
+		y1 := bounds.Min.Y + bounds.Dy()*(r+1)/rows
+		for c := 0; c < cols; c++ {
+			x0 := bounds.Min.X + bounds.Dx()*c/cols
+			x1 := bounds.Min.X + bounds.Dx()*(c+1)/cols
+			rect := image.Rect(x0-overlap, y0-overlap, x1+overlap, y1+overlap).Intersect(bounds)
+			tiles = append(tiles, Tile{Rect: rect, Image: canvas.Crop(img, rect)})
+		}
+	}
+	return tiles, nil
+}
+
+// SplitSize dzieli obraz na kafelki tileW x tileH (ostatnie w wierszu i kolumnie mogą być mniejsze)
+func SplitSize(img image.Image, tileW, tileH, overlap int) ([]Tile, error) {
+	if tileW <= 0 || tileH <= 0 {
+		return nil, fmt.Errorf("invalid tile size %dx%d: width and height must be positive", tileW, tileH)
+	}
+	if err := checkOverlap(overlap, tileW, tileH); err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	tiles := []Tile{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += tileH {
+		for x := bounds.Min.X; x < bounds.Max.X; x += tileW {
+			rect := image.Rect(x-overlap, y-overlap, x+tileW+overlap, y+tileH+overlap).Intersect(bounds)
+			tiles = append(tiles, Tile{Rect: rect, Image: canvas.Crop(img, rect)})
+		}
+	}
+	return tiles, nil
+}
+
+// checkOverlap - zakładka nie może być ujemna ani sięgać rozmiaru kafelka
+func checkOverlap(overlap, tileW, tileH int) error {
+	if overlap < 0 || overlap >= tileW || overlap >= tileH {
+		return fmt.Errorf("invalid overlap %d: must be between 0 and the tile size %dx%d", overlap, tileW, tileH)
+	}
+	return nil
+}
+
+// ContactSheet układa obrazy w siatkę o cols kolumnach. Każdy obraz jest zmniejszany
+// (nigdy powiększany) do komórki cellW x cellH i wyśrodkowany; gdy labels nie jest puste,
+// pod komórką rysowany jest podpis. spacing to odstęp między komórkami, bg to kolor tła (nil = biały).
+func ContactSheet(images []image.Image, labels []string, cols, cellW, cellH, spacing int, bg color.Color) (*image.RGBA, error) {
+	if cellW <= 0 || cellH <= 0 || spacing < 0 {
+		return nil, fmt.Errorf("invalid cell %dx%d with spacing %d", cellW, cellH, spacing)
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	if bg == nil {
+		bg = color.White
+	}
+	rows := (len(images) + cols - 1) / cols
+	rowH := cellH
+	if len(labels) > 0 {
+		rowH += labelHeight
+	}
+	width := cols*cellW + (cols+1)*spacing
+	height := rows*rowH + (rows+1)*spacing
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{Dst: out, Src: image.NewUniform(labelColor(bg)), Face: basicfont.Face7x13}
+	for i, img := range images {
+		cellX := spacing + (i%cols)*(cellW+spacing)
+		cellY := spacing + (i/cols)*(rowH+spacing)
+
+		thumb, err := scale.Thumbnail(img, cellW, cellH, scale.CatmullRom)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		tb := thumb.Bounds()
+		at := image.Pt(cellX+(cellW-tb.Dx())/2, cellY+(cellH-tb.Dy())/2)
+		draw.Draw(out, tb.Add(at), thumb, image.Point{}, draw.Over)
+
+		if i < len(labels) {
+			label := fitLabel(drawer, labels[i], cellW)
+			textW := drawer.MeasureString(label).Round()
+			drawer.Dot = fixed.P(cellX+(cellW-textW)/2, cellY+cellH+labelHeight-4)
+			drawer.DrawString(label)
+		}
+	}
+	return out, nil
+}
+
+// fitLabel skraca podpis, aż zmieści się w szerokości komórki
+func fitLabel(drawer *font.Drawer, label string, width int) string {
+	runes := []rune(label)
+	for len(runes) > 0 && drawer.MeasureString(string(runes)).Round() > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
+
+// labelColor wybiera czarny lub biały tekst, zależnie od jasności tła
+func labelColor(bg color.Color) color.Color {
+	r, g, b, _ := bg.RGBA()
+	if grayscale.ConvertToGrayscale(uint8(r>>8), uint8(g>>8), uint8(b>>8)) > 127 {
+		return color.Black
+	}
+	return color.White
+}
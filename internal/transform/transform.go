@@ -0,0 +1,281 @@
+// Package transform wykonuje przekształcenia afiniczne i perspektywiczne obrazu.
+// Alfa: próbkowanie działa na wartościach premultiplikowanych, tło domyślnie jest przezroczyste.
+package transform
+
+import (
+	"errors"
+	"image"
+	"image-processing/v1/internal/grayscale"
+	"image-processing/v1/internal/interpolate"
+	"image/color"
+	"math"
+)
+
+// Affine - macierz 2x3 zapisana wierszami: x' = a*x + b*y + c, y' = d*x + e*y + f
+type Affine [6]float64
+
+// Homography - macierz 3x3 zapisana wierszami (przekształcenie perspektywiczne)
+type Homography [9]float64
+
+// Options - ustawienia próbkowania (wartości zerowe: interpolacja dwuliniowa, przezroczyste tło)
+type Options struct {
+	Interpolation interpolate.Method
+	Background    color.Color
+}
+
+// Identity zwraca przekształcenie tożsamościowe
+func Identity() Affine {
+	return Affine{1, 0, 0, 0, 1, 0}
+}
+
+// Translate - przesunięcie o (tx, ty)
+func Translate(tx, ty float64) Affine {
+	return Affine{1, 0, tx, 0, 1, ty}
+}
+
+// Scale - skalowanie względem początku układu
+func Scale(sx, sy float64) Affine {
+	return Affine{sx, 0, 0, 0, sy, 0}
+}
+
+// Shear - pochylenie: x' = x + kx*y, y' = y + ky*x
+func Shear(kx, ky float64) Affine {
+	return Affine{1, kx, 0, ky, 1, 0}
+}
+
+// Rotate - obrót o kąt w stopniach wokół początku układu, zgodnie z ruchem wskazówek zegara
+// (oś Y skierowana w dół, tak jak w pakiecie rotate)
+func Rotate(degrees float64) Affine {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return Affine{cos, -sin, 0, sin, cos, 0}
+}
+
+// RotateAround - obrót o kąt w stopniach wokół punktu (cx, cy)
+func RotateAround(degrees, cx, cy float64) Affine {
+	return Compose(Translate(-cx, -cy), Rotate(degrees), Translate(cx, cy))
+}
+
+// Multiply zwraca złożenie m∘n (najpierw n, potem m)
+func (m Affine) Multiply(n Affine) Affine {
+	return Affine{
+		m[0]*n[0] + m[1]*n[3], m[0]*n[1] + m[1]*n[4], m[0]*n[2] + m[1]*n[5] + m[2],
+		m[3]*n[0] + m[4]*n[3], m[3]*n[1] + m[4]*n[4], m[3]*n[2] + m[4]*n[5] + m[5],
+	}
+}
+
+// Compose składa przekształcenia w podanej kolejności (pierwsze jest stosowane jako pierwsze)
+func Compose(steps ...Affine) Affine {
+	out := Identity()
+	for _, s := range steps {
+		out = s.Multiply(out)
+	}
+	return out
+}
+
+// Apply przekształca punkt
+func (m Affine) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[1]*y + m[2], m[3]*x + m[4]*y + m[5]
+}
+
+// Invert zwraca przekształcenie odwrotne
+func (m Affine) Invert() (Affine, error) {
+	det := m[0]*m[4] - m[1]*m[3]
+	if math.Abs(det) < 1e-12 {
+		return Affine{}, errors.New("affine matrix is not invertible")
+	}
+	a, b, d, e := m[4]/det, -m[1]/det, -m[3]/det, m[0]/det
+	return Affine{a, b, -(a*m[2] + b*m[5]), d, e, -(d*m[2] + e*m[5])}, nil
+}
+
+// ToHomography zapisuje przekształcenie afiniczne jako macierz 3x3
+func (m Affine) ToHomography() Homography {
+	return Homography{m[0], m[1], m[2], m[3], m[4], m[5], 0, 0, 1}
+}
+
+// Apply przekształca punkt (z dzieleniem przez współrzędną jednorodną)
+func (h Homography) Apply(x, y float64) (float64, float64) {
+	w := h[6]*x + h[7]*y + h[8]
+	return (h[0]*x + h[1]*y + h[2]) / w, (h[3]*x + h[4]*y + h[5]) / w
+}
+
+// Invert zwraca homografię odwrotną
+func (h Homography) Invert() (Homography, error) {
+	det := h[0]*(h[4]*h[8]-h[5]*h[7]) - h[1]*(h[3]*h[8]-h[5]*h[6]) + h[2]*(h[3]*h[7]-h[4]*h[6])
+	if math.Abs(det) < 1e-12 {
+		return Homography{}, errors.New("homography is not invertible")
+	}
+	return Homography{
+		(h[4]*h[8] - h[5]*h[7]) / det, (h[2]*h[7] - h[1]*h[8]) / det, (h[1]*h[5] - h[2]*h[4]) / det,
+		(h[5]*h[6] - h[3]*h[8]) / det, (h[0]*h[8] - h[2]*h[6]) / det, (h[2]*h[3] - h[0]*h[5]) / det,
+		(h[3]*h[7] - h[4]*h[6]) / det, (h[1]*h[6] - h[0]*h[7]) / det, (h[0]*h[4] - h[1]*h[3]) / det,
+	}, nil
+}
+
+// HomographyFromPoints wyznacza homografię przenoszącą cztery punkty src na cztery punkty dst
+func HomographyFromPoints(src, dst [4][2]float64) (Homography, error) {
+	// Układ 8 równań z 8 niewiadomymi (h[8] = 1)
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y := src[i][0], src[i][1]
+		u, v := dst[i][0], dst[i][1]
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// Eliminacja Gaussa z częściowym wyborem elementu głównego
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return Homography{}, errors.New("points are degenerate (three of them are collinear)")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			f := a[row][col] / a[col][col]
+			for k := col; k < 9; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+		}
+	}
+
+	var h Homography
+	for i := 0; i < 8; i++ {
+		h[i] = a[i][8] / a[i][i]
+	}
+	h[8] = 1
+	return h, nil
+}
+
+// WarpAffine przekształca obraz macierzą afiniczną metodą odwzorowania odwrotnego.
+// Wynik ma rozmiar width x height; współrzędne są ciągłe (środek piksela (x, y) to (x+0.5, y+0.5)).
+func WarpAffine(img image.Image, m Affine, width, height int, opts Options) (*image.RGBA, error) {
+	return WarpPerspective(img, m.ToHomography(), width, height, opts)
+}
+
+// WarpPerspective przekształca obraz homografią metodą odwzorowania odwrotnego
+func WarpPerspective(img image.Image, h Homography, width, height int, opts Options) (*image.RGBA, error) {
+	inv, err := h.Invert()
+	if err != nil {
+		return nil, err
+	}
+	method := opts.Interpolation
+	if method == "" {
+		method = interpolate.Bilinear
+	}
+	var bg color.RGBA64
+	if opts.Background != nil {
+		bg = color.RGBA64Model.Convert(opts.Background).(color.RGBA64)
+	}
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			w := inv[6]*(float64(x)+0.5) + inv[7]*(float64(y)+0.5) + inv[8]
+			if w <= 0 {
+				// Punkt za "horyzontem" perspektywy
+				out.SetRGBA64(x, y, bg)
+				continue
+			}
+			sx, sy := inv.Apply(float64(x)+0.5, float64(y)+0.5)
+			out.SetRGBA64(x, y, interpolate.At(img, sx, sy, method, opts.Background))
+		}
+	}
+	return out, nil
+}
+
+// Rectify prostuje sfotografowany dokument: czworokąt corners (kolejno lewy górny, prawy górny,
+// prawy dolny, lewy dolny) zostaje rozciągnięty na prostokąt width x height.
+// Gdy width lub height wynosi 0, rozmiar jest szacowany z długości boków czworokąta.
+func Rectify(img image.Image, corners [4][2]float64, width, height int, opts Options) (*image.RGBA, error) {
+	if width <= 0 {
+		width = int(math.Round(math.Max(distance(corners[0], corners[1]), distance(corners[3], corners[2]))))
+	}
+	if height <= 0 {
+		height = int(math.Round(math.Max(distance(corners[0], corners[3]), distance(corners[1], corners[2]))))
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("rectified size must be positive")
+	}
+	w, h := float64(width), float64(height)
+	dst := [4][2]float64{{0, 0}, {w, 0}, {w, h}, {0, h}}
+	hom, err := HomographyFromPoints(corners, dst)
+	if err != nil {
+		return nil, err
+	}
+	return WarpPerspective(img, hom, width, height, opts)
+}
+
+// DetectSkew szacuje kąt pochylenia tekstu (w stopniach, z zakresu ±maxAngle) metodą profili projekcji:
+// wybierany jest kąt, dla którego sumy ciemnych pikseli w wierszach mają największą wariancję.
+func DetectSkew(img image.Image, maxAngle float64) float64 {
+	bounds := img.Bounds()
+	// Duże obrazy są próbkowane rzadziej
+	step := 1
+	for bounds.Dx()/step*bounds.Dy()/step > 500000 {
+		step++
+	}
+	points := [][2]float64{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if grayscale.ConvertToGrayscale(uint8(r>>8), uint8(g>>8), uint8(b>>8)) < 128 {
+				points = append(points, [2]float64{float64(x - bounds.Min.X), float64(y - bounds.Min.Y)})
+			}
+		}
+	}
+	if len(points) == 0 {
+		return 0
+	}
+
+	score := func(degrees float64) float64 {
+		sin, cos := math.Sincos(degrees * math.Pi / 180)
+		bins := map[int]float64{}
+		for _, p := range points {
+			// Wiersz punktu po obrocie o -degrees
+			bins[int(math.Floor((-p[0]*sin+p[1]*cos)/float64(step)))]++
+		}
+		mean, sq := 0.0, 0.0
+		for _, v := range bins {
+			mean += v
+			sq += v * v
+		}
+		n := float64(len(bins))
+		mean /= n
+		return sq/n - mean*mean
+	}
+
+	best, bestScore := 0.0, math.Inf(-1)
+	for a := -maxAngle; a <= maxAngle; a += 0.5 {
+		if s := score(a); s > bestScore {
+			best, bestScore = a, s
+		}
+	}
+	// Doprecyzowanie w otoczeniu najlepszego kąta
+	coarse := best
+	for a := coarse - 0.5; a <= coarse+0.5; a += 0.05 {
+		if s := score(a); s > bestScore {
+			best, bestScore = a, s
+		}
+	}
+	return best
+}
+
+// Deskew wykrywa pochylenie (DetectSkew) i obraca obraz tak, by je zniwelować; rozmiar się nie zmienia
+func Deskew(img image.Image, maxAngle float64, opts Options) (*image.RGBA, error) {
+	bounds := img.Bounds()
+	angle := DetectSkew(img, maxAngle)
+	cx := float64(bounds.Min.X+bounds.Max.X) / 2
+	cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+	m := Compose(RotateAround(-angle, cx, cy), Translate(-float64(bounds.Min.X), -float64(bounds.Min.Y)))
+	return WarpAffine(img, m, bounds.Dx(), bounds.Dy(), opts)
+}
+
+func distance(a, b [2]float64) float64 {
+	return math.Hypot(a[0]-b[0], a[1]-b[1])
+}
@@ -1,21 +1,37 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"image"
+	"image-processing/v1/internal/adjust"
 	"image-processing/v1/internal/binarize"
+	"image-processing/v1/internal/canvas"
+	"image-processing/v1/internal/channel"
+	"image-processing/v1/internal/colorspace"
+	"image-processing/v1/internal/composite"
 	"image-processing/v1/internal/convolution"
+	"image-processing/v1/internal/dither"
 	"image-processing/v1/internal/flip"
 	"image-processing/v1/internal/grayscale"
 	"image-processing/v1/internal/histogram"
 	"image-processing/v1/internal/hsl"
+	"image-processing/v1/internal/interpolate"
 	"image-processing/v1/internal/invert"
 	"image-processing/v1/internal/morphology"
+	"image-processing/v1/internal/pyramid"
+	"image-processing/v1/internal/quantize"
 	"image-processing/v1/internal/reduce"
 	"image-processing/v1/internal/rotate"
 	"image-processing/v1/internal/scale"
+	"image-processing/v1/internal/tile"
+	"image-processing/v1/internal/transform"
+	"image/color"
 	"image/jpeg"
+	"io"
 	"log"
+	"math"
 	"os"
 	"time"
 )
@@ -63,6 +79,14 @@ func GetPixelRGB(img image.Image, x, y int) {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		// -h/-help: the flag package has already printed the usage
+		if err := runCommand(os.Args[1:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	start := time.Now()
 
@@ -104,6 +128,70 @@ func main() {
 		return
 	}
 
+	// Tonal adjustments
+	adjusted := adjust.Apply(img, adjust.BrightnessLUT(10).Then(adjust.ContrastLUT(1.2)).Then(adjust.GammaLUT(1.1)))
+	err = SaveImage(adjusted, "output/adjusted.jpg")
+	if err != nil {
+		fmt.Println("Error saving adjusted image:", err)
+	}
+	err = SaveImage(adjust.Exposure(img, -1), "output/exposure_minus1.jpg")
+	if err != nil {
+		fmt.Println("Error saving exposure image:", err)
+	}
+	err = SaveImage(adjust.ApplyLevels(img, adjust.Levels{InBlack: 20, InWhite: 235, Gamma: 1.2, OutBlack: 0, OutWhite: 255}), "output/levels.jpg")
+	if err != nil {
+		fmt.Println("Error saving levels image:", err)
+	}
+	autoLevels, err := adjust.AutoLevels(img, 0.5)
+	if err != nil {
+		fmt.Println("Error computing auto levels:", err)
+	} else if err = SaveImage(autoLevels, "output/auto_levels.jpg"); err != nil {
+		fmt.Println("Error saving auto levels image:", err)
+	}
+
+	// Curves and LUTs
+	sCurve := []adjust.Point{{X: 0, Y: 0}, {X: 64, Y: 48}, {X: 192, Y: 210}, {X: 255, Y: 255}}
+	curved, err := adjust.ApplyCurve(img, sCurve, adjust.CurveLuminance)
+	if err != nil {
+		fmt.Println("Error applying curve:", err)
+	} else if err = SaveImage(curved, "output/curve.jpg"); err != nil {
+		fmt.Println("Error saving curve image:", err)
+	}
+	warm, err := adjust.NewCube3D(17, func(r, g, b float64) (float64, float64, float64) {
+		return r*0.9 + 0.1, g, b * 0.85
+	})
+	if err != nil {
+		fmt.Println("Error building cube LUT:", err)
+	} else {
+		warm.Title = "Warm"
+		err = withOutput("output/warm.cube", func(w io.Writer) error { return adjust.WriteCube(w, warm) })
+		if err != nil {
+			fmt.Println("Error saving cube LUT:", err)
+		} else if cube, err := adjust.LoadCube("output/warm.cube"); err != nil {
+			fmt.Println("Error loading cube LUT:", err)
+		} else if graded, err := cube.Apply(img, adjust.Tetrahedral); err != nil {
+			fmt.Println("Error applying cube LUT:", err)
+		} else if err = SaveImage(graded, "output/graded_warm.jpg"); err != nil {
+			fmt.Println("Error saving graded image:", err)
+		}
+	}
+
+	// Dithering
+	for _, method := range []dither.Method{dither.FloydSteinberg, dither.Atkinson, dither.Bayer8, dither.BlueNoise} {
+		dithered, err := binarize.ApplyDitheredBinarization(img, 128, dither.Options{Method: method, Serpentine: true})
+		if err != nil {
+			fmt.Println("Error dithering image:", err)
+		} else if err = saveOutput(dithered, "output/dither_"+string(method)+".png"); err != nil {
+			fmt.Println("Error saving dithered image:", err)
+		}
+	}
+	reducedDithered, err := reduce.ApplyDitheredBitReduction(img, 2, dither.Options{Method: dither.Stucki, Serpentine: true})
+	if err != nil {
+		fmt.Println("Error dithering image:", err)
+	} else if err = saveOutput(reducedDithered, "output/reduced_dither.png"); err != nil {
+		fmt.Println("Error saving dithered image:", err)
+	}
+
 	// r, g, b := reduce.ReduceBits(255, 16, 32, 4)
 	// println(r, g, b)
 
@@ -115,6 +203,26 @@ func main() {
 		return
 	}
 
+	// Rounded bit reduction, per-channel depths and posterize
+	rgb565, err := reduce.ApplyDepth(img, reduce.RGB565)
+	if err != nil {
+		fmt.Println("Error reducing depth:", err)
+	} else if err = saveOutput(rgb565, "output/reduced_rgb565.png"); err != nil {
+		fmt.Println("Error saving reduced image:", err)
+	}
+	rgb332, err := reduce.ApplyDitheredDepth(img, reduce.RGB332, dither.Options{Method: dither.FloydSteinberg, Serpentine: true})
+	if err != nil {
+		fmt.Println("Error reducing depth:", err)
+	} else if err = saveOutput(rgb332, "output/reduced_rgb332_dither.png"); err != nil {
+		fmt.Println("Error saving reduced image:", err)
+	}
+	posterized, err := reduce.Posterize(img, 4, false)
+	if err != nil {
+		fmt.Println("Error posterizing image:", err)
+	} else if err = saveOutput(posterized, "output/posterized.png"); err != nil {
+		fmt.Println("Error saving posterized image:", err)
+	}
+
 	// Przekonwertuj RGB na HSL
 	hslImg := hsl.ConvertImageToHSL(img)
 
@@ -125,6 +233,65 @@ func main() {
 		fmt.Println("Error saving RGB image:", err)
 		return
 	}
+
+	// Hue, saturation and lightness adjustments
+	err = SaveImage(hsl.Adjust(img, hsl.Adjustment{Hue: 40, Saturation: 0.3, Lightness: 0.05}), "output/hsl_adjusted.jpg")
+	if err != nil {
+		fmt.Println("Error saving HSL adjusted image:", err)
+	}
+	greenless := hsl.AdjustRange(img, hsl.HueRange{Center: 120, Width: 60, Falloff: 30}, hsl.Adjustment{Saturation: -1})
+	err = SaveImage(greenless, "output/hsl_no_greens.jpg")
+	if err != nil {
+		fmt.Println("Error saving selective HSL image:", err)
+	}
+	err = SaveImage(hsl.Colorize(img, 35, 0.35), "output/colorized.jpg")
+	if err != nil {
+		fmt.Println("Error saving colorized image:", err)
+	}
+
+	// Channels: split, swap and alpha mask
+	rgba := channel.Split(img)
+	err = SaveImage(rgba[0], "output/channel_r.jpg")
+	if err != nil {
+		fmt.Println("Error saving channel image:", err)
+	}
+	swapped, err := channel.Swap(img, "bgr")
+	if err != nil {
+		fmt.Println("Error swapping channels:", err)
+	} else if err = SaveImage(swapped, "output/channels_bgr.jpg"); err != nil {
+		fmt.Println("Error saving swapped image:", err)
+	}
+
+	// Color quantization
+	for _, method := range []quantize.Method{quantize.MedianCut, quantize.Octree, quantize.KMeans} {
+		quantized, err := quantize.Quantize(img, 16, method, false)
+		if err != nil {
+			fmt.Println("Error quantizing image:", err)
+		} else if err = saveOutput(quantized, "output/quantized_"+string(method)+".png"); err != nil {
+			fmt.Println("Error saving quantized image:", err)
+		}
+	}
+	quantizedGIF, err := quantize.Quantize(img, 32, quantize.MedianCut, true)
+	if err != nil {
+		fmt.Println("Error quantizing image:", err)
+	} else if err = saveOutput(quantizedGIF, "output/quantized_dither.gif"); err != nil {
+		fmt.Println("Error saving quantized image:", err)
+	}
+
+	// Color spaces: split L*a*b* and Oklab into channel images
+	for _, space := range []colorspace.Space{colorspace.Lab, colorspace.Oklab} {
+		channels, err := colorspace.SplitChannels(img, space)
+		if err != nil {
+			fmt.Println("Error splitting channels:", err)
+			continue
+		}
+		for i, ch := range channels {
+			err = SaveImage(ch, fmt.Sprintf("output/%s_%d.jpg", space, i))
+			if err != nil {
+				fmt.Println("Error saving channel image:", err)
+			}
+		}
+	}
 	fmt.Println("Image processing completed successfully.")
 
 	// Rotate
@@ -134,6 +301,42 @@ func main() {
 		fmt.Println("Error saving rotated image:", err)
 	}
 
+	// Rotate by an arbitrary angle
+	rotatedImg = rotate.RotateAngle(img, 30, rotate.Options{
+		Interpolation: interpolate.Bicubic,
+		Canvas:        rotate.Expand,
+		Background:    color.White,
+	})
+	err = SaveImage(rotatedImg, "output/rotated_30.jpg")
+	if err != nil {
+		fmt.Println("Error saving rotated image:", err)
+	}
+
+	// Transparent rotation flattened onto a white background
+	transparentImg := rotate.RotateAngle(img, -15, rotate.Options{})
+	err = SaveImage(composite.Flatten(transparentImg, color.White), "output/rotated_flattened.jpg")
+	if err != nil {
+		fmt.Println("Error saving rotated image:", err)
+	}
+
+	// Affine and perspective warps
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	affine := transform.Compose(transform.Shear(0.3, 0), transform.Scale(0.8, 0.8), transform.Translate(20, 20))
+	warpedImg, err := transform.WarpAffine(img, affine, bounds.Dx(), bounds.Dy(), transform.Options{Background: color.White})
+	if err != nil {
+		fmt.Println("Error warping image:", err)
+	} else if err = SaveImage(warpedImg, "output/warped_affine.jpg"); err != nil {
+		fmt.Println("Error saving warped image:", err)
+	}
+	corners := [4][2]float64{{0.1 * w, 0.05 * h}, {0.9 * w, 0.15 * h}, {w, h}, {0, 0.9 * h}}
+	rectifiedImg, err := transform.Rectify(img, corners, 0, 0, transform.Options{Interpolation: interpolate.Bicubic})
+	if err != nil {
+		fmt.Println("Error rectifying image:", err)
+	} else if err = SaveImage(rectifiedImg, "output/rectified.jpg"); err != nil {
+		fmt.Println("Error saving rectified image:", err)
+	}
+
 	// Flip vertically
 	flippedImg := flip.FlipVertical(img)
 	err = SaveImage(flippedImg, "output/flipped_vertical.jpg")
@@ -162,6 +365,115 @@ func main() {
 		fmt.Println("Error saving resized bilinear image:", err)
 	}
 
+	// Resize with a named resampling filter
+	for _, name := range []string{"box", "mitchell", "lanczos3"} {
+		filtered, err := scale.ResizeImageByName(img, 200, 200, name)
+		if err != nil {
+			fmt.Println("Error resizing image:", err)
+			continue
+		}
+		err = SaveImage(filtered, "output/resized_"+name+".jpg")
+		if err != nil {
+			fmt.Println("Error saving resized image:", err)
+		}
+	}
+
+	// Aspect-ratio-aware resize modes
+	fillImg, err := scale.Fill(img, 400, 200, scale.CatmullRom)
+	if err != nil {
+		fmt.Println("Error resizing image:", err)
+	} else if err = SaveImage(fillImg, "output/resized_fill.jpg"); err != nil {
+		fmt.Println("Error saving resized image:", err)
+	}
+	padded, err := scale.Pad(img, 400, 200, color.Black, scale.CatmullRom)
+	if err != nil {
+		fmt.Println("Error resizing image:", err)
+	} else if err = SaveImage(padded, "output/resized_pad.jpg"); err != nil {
+		fmt.Println("Error saving resized image:", err)
+	}
+	thumb, err := scale.Thumbnail(img, 128, 128, scale.Lanczos3)
+	if err != nil {
+		fmt.Println("Error creating thumbnail:", err)
+	} else if err = SaveImage(thumb, "output/thumbnail.jpg"); err != nil {
+		fmt.Println("Error saving thumbnail:", err)
+	}
+
+	// Seam carving (content-aware resize)
+	small, err := scale.ScalePercent(img, 50, scale.Box)
+	if err != nil {
+		fmt.Println("Error resizing image:", err)
+	} else {
+		for name, width := range map[string]int{"narrow": 220, "wide": 360} {
+			carved, err := scale.SeamCarve(small, width, small.Bounds().Dy(), nil)
+			if err != nil {
+				fmt.Println("Error seam carving image:", err)
+			} else if err = SaveImage(carved, "output/seam_carved_"+name+".jpg"); err != nil {
+				fmt.Println("Error saving seam carved image:", err)
+			}
+		}
+
+		// Multi-band blending: left half of the image with its mirror
+		mirrored := flip.FlipHorizontal(small)
+		blendMask := image.NewGray(small.Bounds())
+		for y := 0; y < small.Bounds().Dy(); y++ {
+			for x := 0; x < small.Bounds().Dx()/2; x++ {
+				blendMask.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+		blended, err := pyramid.Blend(small, mirrored, blendMask, 5)
+		if err != nil {
+			fmt.Println("Error blending images:", err)
+		} else if err = SaveImage(blended, "output/blended.jpg"); err != nil {
+			fmt.Println("Error saving blended image:", err)
+		}
+	}
+
+	// Crop, trim and pad
+	cropped := canvas.CropPercent(img, 25, 25, 50, 50)
+	err = SaveImage(cropped, "output/cropped.jpg")
+	if err != nil {
+		fmt.Println("Error saving cropped image:", err)
+	}
+	framed, err := canvas.Pad(cropped, 20, 20, 20, 20, canvas.Constant, color.White)
+	if err != nil {
+		fmt.Println("Error padding image:", err)
+	} else if err = SaveImage(canvas.Trim(framed, 0), "output/trimmed.jpg"); err != nil {
+		fmt.Println("Error saving trimmed image:", err)
+	}
+	reflected, err := canvas.Pad(cropped, 40, 40, 40, 40, canvas.Reflect, nil)
+	if err != nil {
+		fmt.Println("Error padding image:", err)
+	} else if err = SaveImage(reflected, "output/padded_reflect.jpg"); err != nil {
+		fmt.Println("Error saving padded image:", err)
+	}
+
+	// Tiles and contact sheet
+	tiles, err := tile.Split(img, 3, 3, 10)
+	if err != nil {
+		fmt.Println("Error splitting image:", err)
+	} else {
+		tileImages := make([]image.Image, len(tiles))
+		tileLabels := make([]string, len(tiles))
+		for i, t := range tiles {
+			tileImages[i] = t.Image
+			tileLabels[i] = fmt.Sprintf("%d,%d", t.Rect.Min.X, t.Rect.Min.Y)
+		}
+		sheet, err := tile.ContactSheet(tileImages, tileLabels, 3, 160, 160, 8, color.White)
+		if err != nil {
+			fmt.Println("Error creating contact sheet:", err)
+		} else if err = SaveImage(sheet, "output/contact_sheet.jpg"); err != nil {
+			fmt.Println("Error saving contact sheet:", err)
+		}
+	}
+
+	// Gamma-correct (linear light) downscaling
+	linear, err := scale.ResizeImageFilter(img, 200, 200, scale.Lanczos3.InLinearLight())
+	if err != nil {
+		fmt.Println("Error resizing image:", err)
+	} else if err = SaveImage(linear, "output/resized_linear.jpg"); err != nil {
+		fmt.Println("Error saving resized image:", err)
+	}
+
 	// Generate histograms
 	err = histogram.GenerateHistogram("input.jpg", "jasnosc", "output/hist")
 	if err != nil {
@@ -172,6 +484,32 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Histogram equalization and CLAHE
+	err = SaveImage(histogram.EqualizeGray(img), "output/hist/equalized_gray.jpg")
+	if err != nil {
+		fmt.Println("Error saving equalized image:", err)
+	}
+	err = SaveImage(histogram.EqualizeLightness(img), "output/hist/equalized_lightness.jpg")
+	if err != nil {
+		fmt.Println("Error saving equalized image:", err)
+	}
+	err = SaveImage(histogram.CLAHELightness(img, 8, 8, 2), "output/hist/clahe.jpg")
+	if err != nil {
+		fmt.Println("Error saving CLAHE image:", err)
+	}
+
+	// Histogram matching to a bell-shaped target distribution
+	var bell [256]float64
+	for i := range bell {
+		bell[i] = math.Exp(-math.Pow(float64(i-128)/40, 2))
+	}
+	matched, err := histogram.MatchDistribution(img, bell, histogram.Luminance)
+	if err != nil {
+		fmt.Println("Error matching histogram:", err)
+	} else if err = SaveImage(matched, "output/hist/matched.jpg"); err != nil {
+		fmt.Println("Error saving matched image:", err)
+	}
+
 	// Convolution
 	grayMatrix := convolution.ConvertToGrayMatrix(img)
 	kernel := [][]float64{
@@ -211,6 +549,32 @@ func main() {
 		return
 	}
 
+	// Histogram of the blurred result compared with the grayscale input
+	err = histogram.PlotHistogram(outImg, "jasnosc", "output/hist/histogram_blur.svg", histogram.PlotOptions{
+		Compare:  grayImg,
+		Filled:   true,
+		Language: "en",
+	})
+	if err != nil {
+		fmt.Println("Error plotting histogram:", err)
+	}
+	err = histogram.PlotHistogram(img, "rgb", "output/hist/histogram_rgb_cumulative_log.png", histogram.PlotOptions{
+		Cumulative: true,
+		LogScale:   true,
+	})
+	if err != nil {
+		fmt.Println("Error plotting histogram:", err)
+	}
+
+	// Blur in linear light
+	result = convolution.Convolve(convolution.LinearizeMatrix(grayMatrix), kernel, convolution.Replicate)
+	outImg = convolution.ConvertGrayMatrixToImage(convolution.DelinearizeMatrix(result))
+	err = SaveImage(outImg, "output/conv_blur_linear.jpg")
+	if err != nil {
+		fmt.Println("Error saving convolved image:", err)
+		return
+	}
+
 	// Morphology operations
 
 	imgMorphology, err := LoadImage("x.png")
@@ -286,6 +650,76 @@ func main() {
 		return
 	}
 
+	// Skeleton endpoints and branch points
+	junctions := morphology.FindJunctions(skeleton)
+	err = SaveImage(morphology.BinaryMatrixToImage(junctions), "output/morphology/junctions.jpg")
+	if err != nil {
+		fmt.Println("Error saving junctions image:", err)
+		return
+	}
+	endpoints := morphology.FindEndpoints(skeleton)
+	err = SaveImage(morphology.BinaryMatrixToImage(endpoints), "output/morphology/endpoints.jpg")
+	if err != nil {
+		fmt.Println("Error saving endpoints image:", err)
+		return
+	}
+
+	// Reconstruction: hole filling and border clearing
+	filled := morphology.FillHoles(bin)
+	err = SaveImage(morphology.BinaryMatrixToImage(filled), "output/morphology/filled.jpg")
+	if err != nil {
+		fmt.Println("Error saving filled image:", err)
+		return
+	}
+	cleared := morphology.ClearBorder(bin)
+	err = SaveImage(morphology.BinaryMatrixToImage(cleared), "output/morphology/cleared_border.jpg")
+	if err != nil {
+		fmt.Println("Error saving cleared border image:", err)
+		return
+	}
+
+	// Distance transform
+	dist := morphology.DistanceTransform(bin, morphology.Euclidean)
+	err = SaveImage(morphology.DistanceMatrixToImage(dist), "output/morphology/distance.jpg")
+	if err != nil {
+		fmt.Println("Error saving distance image:", err)
+		return
+	}
+
+	// Watershed: markery z maksimów mapy odległości, tło jako osobny marker
+	maxDist := 0.0
+	for _, row := range dist {
+		for _, d := range row {
+			maxDist = math.Max(maxDist, d)
+		}
+	}
+	peaks := make([][]uint8, len(dist))
+	negDist := make([][]float64, len(dist))
+	for y := range dist {
+		peaks[y] = make([]uint8, len(dist[y]))
+		negDist[y] = make([]float64, len(dist[y]))
+		for x, d := range dist[y] {
+			if d > 0.5*maxDist {
+				peaks[y][x] = 1
+			}
+			negDist[y][x] = -d
+		}
+	}
+	markers, count := morphology.LabelComponents(peaks)
+	for y := range bin {
+		for x := range bin[y] {
+			if bin[y][x] == 0 {
+				markers[y][x] = count + 1
+			}
+		}
+	}
+	labels := morphology.Watershed(negDist, markers)
+	err = SaveImage(morphology.OverlayLabels(imgMorphology, labels, 0.5), "output/morphology/watershed.jpg")
+	if err != nil {
+		fmt.Println("Error saving watershed image:", err)
+		return
+	}
+
 	end := time.Since(start)
 	fmt.Printf("Processing time: %s\n", end.Round(time.Millisecond))
 }